@@ -0,0 +1,335 @@
+package accounts
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/browser"
+	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+)
+
+// DefaultAccount 默认账号，始终存在且不可删除
+const DefaultAccount = "default"
+
+var (
+	ErrAccountNotFound = errors.New("账号不存在")
+	ErrAccountExists   = errors.New("账号已存在")
+	ErrInvalidName     = errors.New("账号名称只能包含字母、数字、下划线和连字符，长度 1-32")
+	ErrDefaultAccount  = errors.New("默认账号不可删除")
+	ErrNoChallenge     = errors.New("账号没有待处理的验证码")
+)
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// Account 账号信息
+type Account struct {
+	Name        string    `json:"name"`
+	CookiesPath string    `json:"cookies_path"`
+	CreatedAt   time.Time `json:"created_at"`
+	NeedsHuman  bool      `json:"needs_human,omitempty"` // 遇到验证码已暂停，不持久化
+}
+
+// Challenge 账号遇到的验证码。人工处理并恢复前，该账号的浏览器操作全部暂停。
+type Challenge struct {
+	Reason     string    `json:"reason"`
+	URL        string    `json:"url"`
+	DetectedAt time.Time `json:"detected_at"`
+	Screenshot []byte    `json:"-"` // PNG 截图
+}
+
+type entry struct {
+	account   Account
+	pool      *browser.Pool // 首次使用时创建
+	challenge *Challenge    // 非空时账号处于暂停状态
+}
+
+// Registry 账号注册表。
+// 每个账号拥有独立的 cookies 文件和浏览器池，账号列表持久化到本地 JSON 文件。
+type Registry struct {
+	storePath  string
+	poolConfig browser.PoolConfig
+
+	mu       sync.Mutex
+	accounts map[string]*entry
+}
+
+// NewRegistry 创建账号注册表，并从 storePath 加载已保存的账号
+func NewRegistry(storePath string, poolConfig browser.PoolConfig) (*Registry, error) {
+	r := &Registry{
+		storePath:  storePath,
+		poolConfig: poolConfig,
+		accounts:   make(map[string]*entry),
+	}
+
+	r.accounts[DefaultAccount] = &entry{account: Account{
+		Name:        DefaultAccount,
+		CookiesPath: cookies.GetCookiesFilePath(),
+	}}
+
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// List 返回所有账号，按名称排序
+func (r *Registry) List() []Account {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	list := make([]Account, 0, len(r.accounts))
+	for _, e := range r.accounts {
+		account := e.account
+		account.NeedsHuman = e.challenge != nil
+		list = append(list, account)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	return list
+}
+
+// Get 获取账号信息，name 为空时返回默认账号
+func (r *Registry) Get(name string) (Account, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.accounts[normalize(name)]
+	if !ok {
+		return Account{}, errors.Wrap(ErrAccountNotFound, name)
+	}
+	return e.account, nil
+}
+
+// Add 新增账号。新账号需要通过扫码登录后才能使用。
+func (r *Registry) Add(name string) (Account, error) {
+	if !namePattern.MatchString(name) {
+		return Account{}, ErrInvalidName
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.accounts[name]; ok {
+		return Account{}, errors.Wrap(ErrAccountExists, name)
+	}
+
+	account := Account{
+		Name:        name,
+		CookiesPath: cookies.GetAccountCookiesFilePath(name),
+		CreatedAt:   time.Now(),
+	}
+	r.accounts[name] = &entry{account: account}
+
+	if err := r.save(); err != nil {
+		delete(r.accounts, name)
+		return Account{}, err
+	}
+
+	logrus.Infof("新增账号: %s, cookies: %s", name, account.CookiesPath)
+	return account, nil
+}
+
+// Remove 删除账号并关闭其浏览器池。cookies 文件保留，重新添加同名账号即可恢复登录态。
+func (r *Registry) Remove(name string) error {
+	if normalize(name) == DefaultAccount {
+		return ErrDefaultAccount
+	}
+
+	r.mu.Lock()
+	e, ok := r.accounts[name]
+	if !ok {
+		r.mu.Unlock()
+		return errors.Wrap(ErrAccountNotFound, name)
+	}
+	delete(r.accounts, name)
+	err := r.save()
+	if err != nil {
+		r.accounts[name] = e
+	}
+	r.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	if e.pool != nil {
+		e.pool.Close()
+	}
+
+	logrus.Infof("删除账号: %s", name)
+	return nil
+}
+
+// Pool 获取账号对应的浏览器池，首次调用时创建
+func (r *Registry) Pool(name string) (*browser.Pool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.accounts[normalize(name)]
+	if !ok {
+		return nil, errors.Wrap(ErrAccountNotFound, name)
+	}
+
+	if e.pool == nil {
+		cfg := r.poolConfig
+		cfg.CookiesPath = e.account.CookiesPath
+		e.pool = browser.NewPool(cfg)
+	}
+
+	return e.pool, nil
+}
+
+// Pause 记录账号遇到的验证码并暂停该账号，已暂停时保留最早的记录
+func (r *Registry) Pause(name string, challenge Challenge) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.accounts[normalize(name)]
+	if !ok {
+		return errors.Wrap(ErrAccountNotFound, name)
+	}
+	if e.challenge != nil {
+		return nil
+	}
+
+	if challenge.DetectedAt.IsZero() {
+		challenge.DetectedAt = time.Now()
+	}
+	e.challenge = &challenge
+
+	logrus.Warnf("账号 %s 遇到验证码，已暂停: %s", normalize(name), challenge.Reason)
+	return nil
+}
+
+// Challenge 返回账号待处理的验证码，账号未暂停时返回 false
+func (r *Registry) Challenge(name string) (Challenge, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.accounts[normalize(name)]
+	if !ok || e.challenge == nil {
+		return Challenge{}, false
+	}
+	return *e.challenge, true
+}
+
+// Resume 人工处理验证码后恢复账号。
+// 浏览器池会重启以加载人工验证后更新的 cookies。
+func (r *Registry) Resume(name string) error {
+	r.mu.Lock()
+	e, ok := r.accounts[normalize(name)]
+	if !ok {
+		r.mu.Unlock()
+		return errors.Wrap(ErrAccountNotFound, name)
+	}
+	if e.challenge == nil {
+		r.mu.Unlock()
+		return errors.Wrap(ErrNoChallenge, name)
+	}
+	e.challenge = nil
+	pool := e.pool
+	r.mu.Unlock()
+
+	if pool != nil {
+		pool.Reload()
+	}
+
+	logrus.Infof("账号 %s 已恢复", normalize(name))
+	return nil
+}
+
+// Close 关闭所有账号的浏览器池
+func (r *Registry) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range r.accounts {
+		if e.pool != nil {
+			e.pool.Close()
+			e.pool = nil
+		}
+	}
+}
+
+func (r *Registry) load() error {
+	data, err := os.ReadFile(r.storePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to read accounts file")
+	}
+
+	var list []Account
+	if err := json.Unmarshal(data, &list); err != nil {
+		return errors.Wrap(err, "failed to unmarshal accounts file")
+	}
+
+	for _, account := range list {
+		if account.Name == DefaultAccount || !namePattern.MatchString(account.Name) {
+			continue
+		}
+		r.accounts[account.Name] = &entry{account: account}
+	}
+
+	logrus.Infof("从 %s 加载账号 %d 个", r.storePath, len(list))
+	return nil
+}
+
+// save 持久化非默认账号，调用方需持有锁
+func (r *Registry) save() error {
+	list := make([]Account, 0, len(r.accounts))
+	for name, e := range r.accounts {
+		if name == DefaultAccount {
+			continue
+		}
+		list = append(list, e.account)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(r.storePath, data, 0644)
+}
+
+func normalize(name string) string {
+	if name == "" {
+		return DefaultAccount
+	}
+	return name
+}
+
+type contextKey struct{}
+
+// WithAccount 在 context 中记录本次请求使用的账号
+func WithAccount(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, contextKey{}, normalize(name))
+}
+
+// FromContext 读取 context 中的账号，未设置时返回默认账号
+func FromContext(ctx context.Context) string {
+	if name, ok := ctx.Value(contextKey{}).(string); ok {
+		return name
+	}
+	return DefaultAccount
+}
+
+// GetStorePath 获取账号列表文件路径，可通过环境变量 ACCOUNTS_PATH 指定
+func GetStorePath() string {
+	path := os.Getenv("ACCOUNTS_PATH")
+	if path == "" {
+		path = "accounts.json"
+	}
+	return path
+}
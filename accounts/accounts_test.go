@@ -0,0 +1,79 @@
+package accounts
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/browser"
+)
+
+func TestRegistry(t *testing.T) {
+	storePath := filepath.Join(t.TempDir(), "accounts.json")
+
+	r, err := NewRegistry(storePath, browser.PoolConfig{})
+	require.NoError(t, err)
+
+	// 默认账号始终存在
+	list := r.List()
+	require.Len(t, list, 1)
+	require.Equal(t, DefaultAccount, list[0].Name)
+
+	account, err := r.Add("brand-a")
+	require.NoError(t, err)
+	require.Contains(t, account.CookiesPath, ".brand-a")
+
+	_, err = r.Add("brand-a")
+	require.ErrorIs(t, err, ErrAccountExists)
+
+	_, err = r.Add("../evil")
+	require.ErrorIs(t, err, ErrInvalidName)
+
+	// 重新加载后账号仍然存在
+	r2, err := NewRegistry(storePath, browser.PoolConfig{})
+	require.NoError(t, err)
+	loaded, err := r2.Get("brand-a")
+	require.NoError(t, err)
+	require.Equal(t, account.CookiesPath, loaded.CookiesPath)
+
+	require.ErrorIs(t, r2.Remove(DefaultAccount), ErrDefaultAccount)
+	require.NoError(t, r2.Remove("brand-a"))
+	require.ErrorIs(t, r2.Remove("brand-a"), ErrAccountNotFound)
+
+	_, err = r2.Get("brand-a")
+	require.ErrorIs(t, err, ErrAccountNotFound)
+}
+
+func TestAccountContext(t *testing.T) {
+	ctx := context.Background()
+	require.Equal(t, DefaultAccount, FromContext(ctx))
+
+	require.Equal(t, DefaultAccount, FromContext(WithAccount(ctx, "")))
+	require.Equal(t, "brand-a", FromContext(WithAccount(ctx, "brand-a")))
+}
+
+func TestPauseResume(t *testing.T) {
+	r, err := NewRegistry(filepath.Join(t.TempDir(), "accounts.json"), browser.PoolConfig{})
+	require.NoError(t, err)
+
+	_, paused := r.Challenge("")
+	require.False(t, paused)
+	require.ErrorIs(t, r.Resume(""), ErrNoChallenge)
+
+	require.NoError(t, r.Pause("", Challenge{Reason: "first", URL: "https://example.com/captcha"}))
+	require.NoError(t, r.Pause(DefaultAccount, Challenge{Reason: "second"}))
+
+	challenge, paused := r.Challenge(DefaultAccount)
+	require.True(t, paused)
+	require.Equal(t, "first", challenge.Reason, "保留最早的记录")
+	require.False(t, challenge.DetectedAt.IsZero())
+	require.True(t, r.List()[0].NeedsHuman)
+
+	require.NoError(t, r.Resume(DefaultAccount))
+	_, paused = r.Challenge(DefaultAccount)
+	require.False(t, paused)
+	require.False(t, r.List()[0].NeedsHuman)
+
+	require.ErrorIs(t, r.Pause("missing", Challenge{}), ErrAccountNotFound)
+}
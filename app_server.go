@@ -2,34 +2,43 @@ package main
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
 )
 
 // AppServer 应用服务器结构体，封装所有服务和处理器
 type AppServer struct {
 	xiaohongshuService *XiaohongshuService
+	accounts           *accounts.Registry
 	mcpServer          *mcp.Server
+	resourceSubs       *resourceSubscriptions
 	router             *gin.Engine
 	httpServer         *http.Server
 }
 
 // NewAppServer 创建新的应用服务器实例
-func NewAppServer(xiaohongshuService *XiaohongshuService) *AppServer {
+func NewAppServer(xiaohongshuService *XiaohongshuService, registry *accounts.Registry) *AppServer {
 	appServer := &AppServer{
 		xiaohongshuService: xiaohongshuService,
+		accounts:           registry,
+		resourceSubs:       newResourceSubscriptions(),
 	}
 
 	// 初始化 MCP Server（需要在创建 appServer 之后，因为工具注册需要访问 appServer）
 	appServer.mcpServer = InitMCPServer(appServer)
 
+	// 评论、点赞、关注等写操作成功后，通知订阅了对应资源的 MCP 客户端
+	xiaohongshuService.OnChange(appServer.notifyResourceChanged)
+
 	return appServer
 }
 
@@ -58,7 +67,7 @@ func (s *AppServer) Start(port string) error {
 
 	logrus.Infof("正在关闭服务器...")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), configs.Get().Server.ShutdownTimeout.Duration)
 	defer cancel()
 
 	if err := s.httpServer.Shutdown(ctx); err != nil {
@@ -69,3 +78,35 @@ func (s *AppServer) Start(port string) error {
 
 	return nil
 }
+
+// ServeStdio 在 in、out 上提供 MCP 服务，供桌面 MCP 客户端直接启动本程序使用。
+// in、out 为进程原始的标准输入输出，调用前 os.Stdout 应已改指向标准错误。客户端断开或收到中断信号时返回。
+func (s *AppServer) ServeStdio(in io.ReadCloser, out io.WriteCloser) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	session, err := s.mcpServer.Connect(ctx, &ioTransport{in: in, out: out}, nil)
+	if err != nil {
+		return err
+	}
+	logrus.Infof("MCP 服务已通过 stdio 启动")
+
+	done := make(chan error, 1)
+	go func() {
+		done <- session.Wait()
+	}()
+
+	select {
+	case <-ctx.Done():
+		logrus.Infof("正在关闭 stdio MCP 服务...")
+		session.Close()
+		<-done
+	case err := <-done:
+		if err != nil {
+			logrus.Infof("MCP 客户端已断开: %v", err)
+		} else {
+			logrus.Infof("MCP 客户端已断开")
+		}
+	}
+	return nil
+}
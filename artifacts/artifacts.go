@@ -0,0 +1,206 @@
+package artifacts
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// 快照中的文件名
+const (
+	FileScreenshot = "screenshot.png"
+	FileHTML       = "page.html"
+
+	fileMeta = "meta.json"
+)
+
+// DefaultMaxArtifacts 默认最多保留的快照数量，超出后删除最早的快照
+const DefaultMaxArtifacts = 100
+
+var ErrNotFound = errors.New("调试快照不存在")
+
+var idPattern = regexp.MustCompile(`^\d{8}-\d{6}-[0-9a-f]{6}$`)
+
+// Meta 一次失败快照的描述信息
+type Meta struct {
+	ID      string    `json:"id"`
+	Time    time.Time `json:"time"`
+	Account string    `json:"account"`
+	Action  string    `json:"action"`
+	URL     string    `json:"url"`
+	Title   string    `json:"title,omitempty"`
+	Error   string    `json:"error"`
+	Files   []string  `json:"files"`
+}
+
+// Store 调试快照目录，每个快照一个子目录，包含 meta.json、整页截图和页面 HTML
+type Store struct {
+	mu  sync.Mutex
+	dir string
+	max int
+}
+
+// Open 打开快照目录，不存在时创建。max 为最多保留的快照数量，<=0 时使用默认值。
+func Open(dir string, max int) (*Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, pkgerrors.Wrap(err, "failed to create debug artifacts dir")
+	}
+	if max <= 0 {
+		max = DefaultMaxArtifacts
+	}
+	return &Store{dir: dir, max: max}, nil
+}
+
+// GetDir 获取快照目录，可通过环境变量 DEBUG_ARTIFACTS_DIR 指定
+func GetDir() string {
+	dir := os.Getenv("DEBUG_ARTIFACTS_DIR")
+	if dir == "" {
+		dir = "debug_artifacts"
+	}
+	return dir
+}
+
+// Save 保存一次快照，files 为文件名到内容的映射，内容为空的文件不保存。返回快照ID。
+func (s *Store) Save(meta Meta, files map[string][]byte) (string, error) {
+	if meta.Time.IsZero() {
+		meta.Time = time.Now()
+	}
+	id, err := newID(meta.Time)
+	if err != nil {
+		return "", err
+	}
+	meta.ID = id
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir := filepath.Join(s.dir, id)
+	if err := os.Mkdir(dir, 0700); err != nil {
+		return "", pkgerrors.Wrap(err, "failed to create artifact dir")
+	}
+
+	meta.Files = meta.Files[:0]
+	for name, data := range files {
+		if len(data) == 0 {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			return "", pkgerrors.Wrapf(err, "failed to write artifact file %s", name)
+		}
+		meta.Files = append(meta.Files, name)
+	}
+	sort.Strings(meta.Files)
+
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "failed to marshal artifact meta")
+	}
+	if err := os.WriteFile(filepath.Join(dir, fileMeta), data, 0600); err != nil {
+		return "", pkgerrors.Wrap(err, "failed to write artifact meta")
+	}
+
+	s.pruneLocked()
+	return id, nil
+}
+
+// Get 读取快照信息
+func (s *Store) Get(id string) (*Meta, error) {
+	if !idPattern.MatchString(id) {
+		return nil, ErrNotFound
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.dir, id, fileMeta))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "failed to read artifact meta")
+	}
+
+	var meta Meta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, pkgerrors.Wrap(err, "failed to parse artifact meta")
+	}
+	return &meta, nil
+}
+
+// FilePath 返回快照中指定文件的路径，文件不属于该快照时返回 ErrNotFound
+func (s *Store) FilePath(id, name string) (string, error) {
+	meta, err := s.Get(id)
+	if err != nil {
+		return "", err
+	}
+	if !slices.Contains(meta.Files, name) {
+		return "", ErrNotFound
+	}
+	return filepath.Join(s.dir, id, name), nil
+}
+
+// pruneLocked 删除超出数量上限的最早快照。快照ID以时间开头，按名称排序即按时间排序。
+func (s *Store) pruneLocked() {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		logrus.Warnf("读取调试快照目录失败: %v", err)
+		return
+	}
+
+	var ids []string
+	for _, e := range entries {
+		if e.IsDir() && idPattern.MatchString(e.Name()) {
+			ids = append(ids, e.Name())
+		}
+	}
+	if len(ids) <= s.max {
+		return
+	}
+
+	sort.Strings(ids)
+	for _, id := range ids[:len(ids)-s.max] {
+		if err := os.RemoveAll(filepath.Join(s.dir, id)); err != nil {
+			logrus.Warnf("删除调试快照 %s 失败: %v", id, err)
+		}
+	}
+}
+
+func newID(t time.Time) (string, error) {
+	b := make([]byte, 3)
+	if _, err := rand.Read(b); err != nil {
+		return "", pkgerrors.Wrap(err, "failed to generate artifact id")
+	}
+	return t.Format("20060102-150405") + "-" + hex.EncodeToString(b), nil
+}
+
+// Error 附带调试快照ID的错误
+type Error struct {
+	ID  string
+	Err error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v（调试快照: %s）", e.Err, e.ID)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// IDOf 返回错误链中的调试快照ID，没有时返回空字符串
+func IDOf(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.ID
+	}
+	return ""
+}
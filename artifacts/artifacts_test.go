@@ -0,0 +1,64 @@
+package artifacts
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreSaveAndGet(t *testing.T) {
+	store, err := Open(t.TempDir(), 0)
+	require.NoError(t, err)
+
+	id, err := store.Save(Meta{Account: "a", Action: "publish", URL: "https://creator.xiaohongshu.com/publish", Error: "boom"},
+		map[string][]byte{FileScreenshot: []byte("png"), FileHTML: []byte("<html></html>"), "empty.txt": nil})
+	require.NoError(t, err)
+
+	meta, err := store.Get(id)
+	require.NoError(t, err)
+	require.Equal(t, id, meta.ID)
+	require.Equal(t, "boom", meta.Error)
+	require.Equal(t, []string{FileHTML, FileScreenshot}, meta.Files)
+
+	path, err := store.FilePath(id, FileHTML)
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "<html></html>", string(data))
+
+	_, err = store.FilePath(id, "empty.txt")
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = store.FilePath(id, "../"+id+"/meta.json")
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = store.Get("../etc")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestStorePrune(t *testing.T) {
+	store, err := Open(t.TempDir(), 2)
+	require.NoError(t, err)
+
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	var ids []string
+	for i := 0; i < 3; i++ {
+		id, err := store.Save(Meta{Time: base.Add(time.Duration(i) * time.Second)}, nil)
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	_, err = store.Get(ids[0])
+	require.ErrorIs(t, err, ErrNotFound, "最早的快照被删除")
+	_, err = store.Get(ids[2])
+	require.NoError(t, err)
+}
+
+func TestIDOf(t *testing.T) {
+	err := fmt.Errorf("发布失败: %w", &Error{ID: "20250101-120000-abcdef", Err: errors.New("boom")})
+	require.Equal(t, "20250101-120000-abcdef", IDOf(err))
+	require.Equal(t, "发布失败: boom（调试快照: 20250101-120000-abcdef）", err.Error())
+	require.Equal(t, "", IDOf(errors.New("plain")))
+}
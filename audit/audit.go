@@ -0,0 +1,213 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Action 记录的写操作类型
+type Action string
+
+const (
+	ActionPublish    Action = "publish"
+	ActionSaveDraft  Action = "save_draft"
+	ActionComment    Action = "comment"
+	ActionReply      Action = "reply"
+	ActionLike       Action = "like"
+	ActionUnlike     Action = "unlike"
+	ActionFavorite   Action = "favorite"
+	ActionUnfavorite Action = "unfavorite"
+	ActionFollow     Action = "follow"
+	ActionUnfollow   Action = "unfollow"
+)
+
+// 操作结果
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailed  = "failed"
+)
+
+// 操作来源渠道
+const (
+	ChannelHTTP     = "http"
+	ChannelMCP      = "mcp"
+	ChannelSchedule = "schedule"
+)
+
+const (
+	// DefaultQueryLimit 查询默认返回的条数
+	DefaultQueryLimit = 100
+	// MaxQueryLimit 单次查询最多返回的条数
+	MaxQueryLimit = 1000
+)
+
+// Source 写操作的发起方
+type Source struct {
+	Channel string // http|mcp|schedule
+	Name    string // MCP 工具名、HTTP 接口（如 "POST /api/v1/feeds/like"）或定时任务ID
+	Client  string // MCP 客户端名称或 HTTP 请求的 User-Agent
+	Remote  string // HTTP 请求的客户端IP
+}
+
+type sourceKey struct{}
+
+// WithSource 在 context 中记录操作来源
+func WithSource(ctx context.Context, source Source) context.Context {
+	return context.WithValue(ctx, sourceKey{}, source)
+}
+
+// SourceFrom 读取 context 中的操作来源，未设置时返回空值
+func SourceFrom(ctx context.Context) Source {
+	source, _ := ctx.Value(sourceKey{}).(Source)
+	return source
+}
+
+// Entry 一条审计记录
+type Entry struct {
+	Time    time.Time      `json:"time"`
+	Account string         `json:"account"`
+	Action  Action         `json:"action"`
+	Channel string         `json:"channel,omitempty"`
+	Source  string         `json:"source,omitempty"`
+	Client  string         `json:"client,omitempty"`
+	Remote  string         `json:"remote,omitempty"`
+	JobID   string         `json:"job_id,omitempty"`
+	Args    map[string]any `json:"args,omitempty"`
+	Outcome string         `json:"outcome"`
+	Error   string         `json:"error,omitempty"`
+	Result  any            `json:"result,omitempty"`
+}
+
+// Filter 查询条件，零值字段不参与过滤
+type Filter struct {
+	Since   time.Time
+	Until   time.Time
+	Action  Action
+	Account string
+	Outcome string
+	Limit   int
+}
+
+func (f Filter) match(e *Entry) bool {
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && e.Time.After(f.Until) {
+		return false
+	}
+	if f.Action != "" && e.Action != f.Action {
+		return false
+	}
+	if f.Account != "" && e.Account != f.Account {
+		return false
+	}
+	if f.Outcome != "" && e.Outcome != f.Outcome {
+		return false
+	}
+	return true
+}
+
+// Log 只追加的 JSONL 审计日志，每行一条记录
+type Log struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// Open 打开审计日志文件，不存在时创建
+func Open(path string) (*Log, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open audit log")
+	}
+	return &Log{path: path, file: file}, nil
+}
+
+// GetLogPath 获取审计日志路径，可通过环境变量 AUDIT_LOG_PATH 指定
+func GetLogPath() string {
+	path := os.Getenv("AUDIT_LOG_PATH")
+	if path == "" {
+		path = "audit.jsonl"
+	}
+	return path
+}
+
+// Record 追加一条记录并落盘
+func (l *Log) Record(entry Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal audit entry")
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err := l.file.Write(data); err != nil {
+		return errors.Wrap(err, "failed to write audit log")
+	}
+	return l.file.Sync()
+}
+
+// Query 按条件查询记录，按时间倒序返回最近的 Limit 条
+func (l *Log) Query(filter Filter) ([]Entry, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultQueryLimit
+	}
+	if limit > MaxQueryLimit {
+		limit = MaxQueryLimit
+	}
+
+	// 持有锁读取，避免读到写了一半的行
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := os.Open(l.path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open audit log")
+	}
+	defer file.Close()
+
+	var matched []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			logrus.Warnf("跳过无法解析的审计记录: %v", err)
+			continue
+		}
+		if filter.match(&entry) {
+			matched = append(matched, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read audit log")
+	}
+
+	// 文件按写入顺序追加，倒序取最近的记录
+	entries := make([]Entry, 0, min(limit, len(matched)))
+	for i := len(matched) - 1; i >= 0 && len(entries) < limit; i-- {
+		entries = append(entries, matched[i])
+	}
+	return entries, nil
+}
+
+// Close 关闭审计日志
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
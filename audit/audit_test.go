@@ -0,0 +1,62 @@
+package audit
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogRecordAndQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := Open(path)
+	require.NoError(t, err)
+
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, l.Record(Entry{Time: base, Account: "a", Action: ActionLike, Outcome: OutcomeSuccess,
+		Args: map[string]any{"feed_id": "f1"}}))
+	require.NoError(t, l.Record(Entry{Time: base.Add(time.Minute), Account: "a", Action: ActionComment, Outcome: OutcomeFailed, Error: "boom"}))
+	require.NoError(t, l.Record(Entry{Time: base.Add(2 * time.Minute), Account: "b", Action: ActionLike, Outcome: OutcomeSuccess}))
+	require.NoError(t, l.Close())
+
+	// 重新打开后追加写入，已有记录保留
+	l, err = Open(path)
+	require.NoError(t, err)
+	defer l.Close()
+	require.NoError(t, l.Record(Entry{Time: base.Add(3 * time.Minute), Account: "a", Action: ActionPublish, Outcome: OutcomeSuccess}))
+
+	entries, err := l.Query(Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+	require.Equal(t, ActionPublish, entries[0].Action, "按时间倒序")
+	require.Equal(t, "f1", entries[3].Args["feed_id"])
+
+	entries, err = l.Query(Filter{Action: ActionLike})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	entries, err = l.Query(Filter{Since: base.Add(time.Minute), Until: base.Add(2 * time.Minute)})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, "b", entries[0].Account)
+
+	entries, err = l.Query(Filter{Account: "a", Outcome: OutcomeFailed})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "boom", entries[0].Error)
+
+	entries, err = l.Query(Filter{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, ActionPublish, entries[0].Action)
+}
+
+func TestSource(t *testing.T) {
+	ctx := context.Background()
+	require.Equal(t, Source{}, SourceFrom(ctx))
+
+	source := Source{Channel: ChannelMCP, Name: "like_feed", Client: "test-client"}
+	require.Equal(t, source, SourceFrom(WithSource(ctx, source)))
+}
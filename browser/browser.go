@@ -7,7 +7,8 @@ import (
 )
 
 type browserConfig struct {
-	binPath string
+	binPath     string
+	cookiesPath string
 }
 
 type Option func(*browserConfig)
@@ -18,6 +19,13 @@ func WithBinPath(binPath string) Option {
 	}
 }
 
+// WithCookiesPath 指定加载的 cookies 文件，默认使用 cookies.GetCookiesFilePath()
+func WithCookiesPath(cookiesPath string) Option {
+	return func(c *browserConfig) {
+		c.cookiesPath = cookiesPath
+	}
+}
+
 func NewBrowser(headless bool, options ...Option) *headless_browser.Browser {
 	cfg := &browserConfig{}
 	for _, opt := range options {
@@ -32,7 +40,10 @@ func NewBrowser(headless bool, options ...Option) *headless_browser.Browser {
 	}
 
 	// 加载 cookies
-	cookiePath := cookies.GetCookiesFilePath()
+	cookiePath := cfg.cookiesPath
+	if cookiePath == "" {
+		cookiePath = cookies.GetCookiesFilePath()
+	}
 	cookieLoader := cookies.NewLoadCookie(cookiePath)
 
 	if data, err := cookieLoader.LoadCookies(); err == nil {
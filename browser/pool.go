@@ -0,0 +1,351 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/headless_browser"
+)
+
+const (
+	defaultPoolSize            = 2
+	defaultIdleTimeout         = 10 * time.Minute
+	defaultHealthCheckInterval = time.Minute
+	healthCheckTimeout         = 5 * time.Second
+)
+
+// ErrPoolClosed 浏览器池已关闭
+var ErrPoolClosed = errors.New("browser pool is closed")
+
+// PoolConfig 浏览器池配置
+type PoolConfig struct {
+	// Size 最多同时运行的浏览器实例数，也是最大并发租用数
+	Size int
+	// IdleTimeout 空闲超过该时长的浏览器实例会被回收
+	IdleTimeout time.Duration
+	// HealthCheckInterval 后台健康检查与空闲回收的间隔
+	HealthCheckInterval time.Duration
+
+	Headless    bool
+	BinPath     string
+	CookiesPath string // 为空时使用默认 cookies 文件
+}
+
+// instance 池中的一个浏览器实例
+type instance struct {
+	browser    *headless_browser.Browser
+	rod        *rod.Browser
+	generation int
+	lastUsed   time.Time
+}
+
+// Pool 长驻的浏览器池。
+// 每次租用独占一个浏览器实例并在其中打开新页面，归还时关闭页面、保留浏览器，
+// 从而避免每次调用都重新启动 Chrome 和加载 cookies。
+type Pool struct {
+	cfg PoolConfig
+
+	slots chan struct{} // 限制并发租用数
+
+	mu         sync.Mutex
+	idle       []*instance
+	generation int // Reload 后递增，旧代实例归还时直接关闭
+	closed     bool
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// Lease 一次页面租用，使用完毕后必须调用 Release
+type Lease struct {
+	Page *rod.Page
+
+	pool *Pool
+	inst *instance
+	once sync.Once
+}
+
+// NewPool 创建浏览器池。浏览器实例按需懒启动。
+func NewPool(cfg PoolConfig) *Pool {
+	if cfg.Size <= 0 {
+		cfg.Size = defaultPoolSize
+	}
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = defaultIdleTimeout
+	}
+	if cfg.HealthCheckInterval <= 0 {
+		cfg.HealthCheckInterval = defaultHealthCheckInterval
+	}
+
+	p := &Pool{
+		cfg:   cfg,
+		slots: make(chan struct{}, cfg.Size),
+		done:  make(chan struct{}),
+	}
+
+	p.wg.Add(1)
+	go p.maintain()
+
+	logrus.Infof("浏览器池已创建: size=%d, idle_timeout=%s", cfg.Size, cfg.IdleTimeout)
+
+	return p
+}
+
+// Acquire 租用一个页面。池满时阻塞等待，直到有空闲实例或 ctx 结束。
+func (p *Pool) Acquire(ctx context.Context) (*Lease, error) {
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-p.done:
+		return nil, ErrPoolClosed
+	}
+
+	inst, err := p.takeInstance()
+	if err != nil {
+		<-p.slots
+		return nil, err
+	}
+
+	page, err := newPage(inst)
+	if err != nil {
+		// 实例可能在健康检查之后崩溃，换一个新实例重试一次
+		logrus.Warnf("浏览器实例创建页面失败，重新启动: %v", err)
+		closeInstance(inst)
+
+		if inst, err = p.launch(); err == nil {
+			page, err = newPage(inst)
+		}
+		if err != nil {
+			if inst != nil {
+				closeInstance(inst)
+			}
+			<-p.slots
+			return nil, errors.Wrap(err, "failed to open browser page")
+		}
+	}
+
+	return &Lease{Page: page, pool: p, inst: inst}, nil
+}
+
+// Release 关闭页面并将浏览器实例归还到池中，可重复调用
+func (l *Lease) Release() {
+	l.once.Do(func() {
+		healthy := true
+		if err := l.Page.Close(); err != nil {
+			logrus.Warnf("关闭页面失败，丢弃浏览器实例: %v", err)
+			healthy = false
+		}
+
+		l.pool.putInstance(l.inst, healthy)
+		<-l.pool.slots
+	})
+}
+
+// Reload 让池中所有浏览器实例失效，后续租用会以最新的 cookies 重新启动浏览器。
+// 正在使用中的实例在归还时关闭。
+func (p *Pool) Reload() {
+	p.mu.Lock()
+	p.generation++
+	idle := p.idle
+	p.idle = nil
+	p.mu.Unlock()
+
+	for _, inst := range idle {
+		closeInstance(inst)
+	}
+
+	logrus.Infof("浏览器池已重置，关闭空闲实例 %d 个", len(idle))
+}
+
+// Close 关闭浏览器池及所有空闲浏览器实例
+func (p *Pool) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	idle := p.idle
+	p.idle = nil
+	p.mu.Unlock()
+
+	close(p.done)
+	p.wg.Wait()
+
+	for _, inst := range idle {
+		closeInstance(inst)
+	}
+}
+
+// takeInstance 取出一个健康的空闲实例，没有则启动新实例
+func (p *Pool) takeInstance() (*instance, error) {
+	for {
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			return nil, ErrPoolClosed
+		}
+		if len(p.idle) == 0 {
+			p.mu.Unlock()
+			return p.launch()
+		}
+		// 后进先出，尽量复用最近使用过的实例
+		inst := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+		p.mu.Unlock()
+
+		if err := checkHealth(inst); err != nil {
+			logrus.Warnf("浏览器实例健康检查失败，重新启动: %v", err)
+			closeInstance(inst)
+			continue
+		}
+
+		return inst, nil
+	}
+}
+
+func (p *Pool) putInstance(inst *instance, healthy bool) {
+	p.mu.Lock()
+	if !healthy || p.closed || inst.generation != p.generation {
+		p.mu.Unlock()
+		closeInstance(inst)
+		return
+	}
+
+	inst.lastUsed = time.Now()
+	p.idle = append(p.idle, inst)
+	p.mu.Unlock()
+}
+
+func (p *Pool) launch() (*instance, error) {
+	p.mu.Lock()
+	generation := p.generation
+	p.mu.Unlock()
+
+	inst, err := launchInstance(p.cfg)
+	if err != nil {
+		return nil, err
+	}
+	inst.generation = generation
+
+	return inst, nil
+}
+
+// maintain 定期回收空闲过久的实例，并剔除已崩溃的实例
+func (p *Pool) maintain() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.cfg.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			p.evict()
+		}
+	}
+}
+
+func (p *Pool) evict() {
+	p.mu.Lock()
+	var keep, drop []*instance
+	for _, inst := range p.idle {
+		if time.Since(inst.lastUsed) > p.cfg.IdleTimeout {
+			drop = append(drop, inst)
+			continue
+		}
+		keep = append(keep, inst)
+	}
+	p.idle = keep
+	p.mu.Unlock()
+
+	for _, inst := range drop {
+		logrus.Infof("回收空闲浏览器实例，空闲时长 %s", time.Since(inst.lastUsed).Round(time.Second))
+		closeInstance(inst)
+	}
+
+	// 健康检查不持锁进行，实例留在池中，检查期间仍可被租用（租用时会再次检查）。
+	// 若先移出再放回，并发的 Acquire 会看到空池并启动新实例，放回后空闲实例数可能超过池大小。
+	p.mu.Lock()
+	checking := append([]*instance(nil), p.idle...)
+	p.mu.Unlock()
+
+	for _, inst := range checking {
+		if err := checkHealth(inst); err != nil {
+			if p.removeIdle(inst) {
+				logrus.Warnf("剔除已崩溃的浏览器实例: %v", err)
+				closeInstance(inst)
+			}
+		}
+	}
+}
+
+// removeIdle 从空闲列表中移除实例，实例已被租用或关闭时返回 false
+func (p *Pool) removeIdle(inst *instance) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, idle := range p.idle {
+		if idle == inst {
+			p.idle = append(p.idle[:i], p.idle[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// launchInstance 启动浏览器，将 go-rod 启动时的 panic 转换为 error
+func launchInstance(cfg PoolConfig) (inst *instance, err error) {
+	var b *headless_browser.Browser
+	defer func() {
+		if r := recover(); r != nil {
+			if b != nil {
+				closeInstance(&instance{browser: b})
+			}
+			err = fmt.Errorf("failed to launch browser: %v", r)
+		}
+	}()
+
+	b = NewBrowser(cfg.Headless, WithBinPath(cfg.BinPath), WithCookiesPath(cfg.CookiesPath))
+
+	// headless_browser 未暴露底层 rod.Browser，通过一个探测页面获取
+	probe := b.NewPage()
+	rb := probe.Browser()
+	_ = probe.Close()
+
+	return &instance{browser: b, rod: rb, lastUsed: time.Now()}, nil
+}
+
+func newPage(inst *instance) (page *rod.Page, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to create page: %v", r)
+		}
+	}()
+
+	return inst.browser.NewPage(), nil
+}
+
+func checkHealth(inst *instance) error {
+	_, err := proto.BrowserGetVersion{}.Call(inst.rod.Timeout(healthCheckTimeout))
+	return err
+}
+
+func closeInstance(inst *instance) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Debugf("关闭浏览器实例时出错: %v", r)
+		}
+	}()
+
+	inst.browser.Close()
+}
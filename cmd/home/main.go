@@ -37,8 +37,7 @@ func handleHomePage(suffix string) {
 	logrus.Info("欢迎使用小红书首页交互程序")
 
 	if suffix != "" {
-		base := cookies.GetCookiesFilePath()
-		routed := base + "." + suffix
+		routed := cookies.GetAccountCookiesFilePath(suffix)
 		_ = os.Setenv("COOKIES_PATH", routed)
 		logrus.Infof("使用路由 cookies 文件: %s", routed)
 	}
@@ -1,24 +1,10 @@
 package configs
 
-var (
-	useHeadless = true
-
-	binPath = ""
-)
-
-func InitHeadless(h bool) {
-	useHeadless = h
-}
-
 // IsHeadless 是否无头模式。
 func IsHeadless() bool {
-	return useHeadless
-}
-
-func SetBinPath(b string) {
-	binPath = b
+	return Get().Browser.Headless
 }
 
 func GetBinPath() string {
-	return binPath
+	return Get().Browser.BinPath
 }
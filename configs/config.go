@@ -0,0 +1,410 @@
+package configs
+
+import (
+	"bytes"
+	"encoding"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// envPrefix 环境变量覆盖的前缀，变量名由配置项路径转为大写得到，如 XHS_SERVER_ADDR、XHS_DELAYS_HUMAN_MIN
+const envPrefix = "XHS_"
+
+// Config 服务配置。
+// 加载顺序为：默认值、配置文件、环境变量，后者覆盖前者；命令行参数由 main 在最后覆盖。
+type Config struct {
+	Server     ServerConfig     `yaml:"server" json:"server"`
+	Browser    BrowserConfig    `yaml:"browser" json:"browser"`
+	Timeouts   TimeoutsConfig   `yaml:"timeouts" json:"timeouts"`
+	Delays     DelaysConfig     `yaml:"delays" json:"delays"`
+	RateLimits RateLimitsConfig `yaml:"rate_limits" json:"rate_limits"`
+	Schedule   ScheduleConfig   `yaml:"schedule" json:"schedule"`
+	Storage    StorageConfig    `yaml:"storage" json:"storage"`
+	Features   FeaturesConfig   `yaml:"features" json:"features"`
+}
+
+// MCP 服务的传输方式
+const (
+	TransportHTTP  = "http"  // Streamable HTTP，同时提供 HTTP API
+	TransportStdio = "stdio" // 标准输入输出，由 MCP 客户端直接启动进程
+)
+
+type ServerConfig struct {
+	Transport string `yaml:"transport" json:"transport"`
+	Addr      string `yaml:"addr" json:"addr"`
+	// ShutdownTimeout 退出时等待请求处理完成的时长
+	ShutdownTimeout Duration `yaml:"shutdown_timeout" json:"shutdown_timeout"`
+}
+
+type BrowserConfig struct {
+	Headless        bool     `yaml:"headless" json:"headless"`
+	BinPath         string   `yaml:"bin_path" json:"bin_path" env:"ROD_BROWSER_BIN"`
+	PoolSize        int      `yaml:"pool_size" json:"pool_size"`
+	PoolIdleTimeout Duration `yaml:"pool_idle_timeout" json:"pool_idle_timeout"`
+}
+
+// TimeoutsConfig 浏览器操作的超时时间
+type TimeoutsConfig struct {
+	PageAction     Duration `yaml:"page_action" json:"page_action"`         // 点赞、收藏、关注、评论、搜索、主页等普通页面操作
+	ChannelFeeds   Duration `yaml:"channel_feeds" json:"channel_feeds"`     // 发现页频道滚动加载
+	Upload         Duration `yaml:"upload" json:"upload"`                   // 上传图片/视频并填写发布内容
+	ImageUpload    Duration `yaml:"image_upload" json:"image_upload"`       // 等待图片上传完成
+	VideoProcess   Duration `yaml:"video_process" json:"video_process"`     // 等待视频上传处理完成
+	PublishConfirm Duration `yaml:"publish_confirm" json:"publish_confirm"` // 点击发布后等待提交结果
+	ElementWait    Duration `yaml:"element_wait" json:"element_wait"`       // 等待发布页、草稿箱的按钮和输入框出现
+	ElementProbe   Duration `yaml:"element_probe" json:"element_probe"`     // 查找可能不存在的元素，如可选的标签页、确认弹窗
+	EditorLoad     Duration `yaml:"editor_load" json:"editor_load"`         // 从草稿箱打开编辑器
+	FeedDetail     Duration `yaml:"feed_detail" json:"feed_detail"`         // 笔记详情及评论加载
+	ReplyComment   Duration `yaml:"reply_comment" json:"reply_comment"`     // 在评论区查找并回复评论
+	LoginWait      Duration `yaml:"login_wait" json:"login_wait"`           // 等待扫码登录
+	Snapshot       Duration `yaml:"snapshot" json:"snapshot"`               // 保存失败快照
+}
+
+// DelaysConfig 模拟真人操作的随机延迟
+type DelaysConfig struct {
+	Human         DelayRange `yaml:"human" json:"human"`
+	Reaction      DelayRange `yaml:"reaction" json:"reaction"`
+	Hover         DelayRange `yaml:"hover" json:"hover"`
+	Read          DelayRange `yaml:"read" json:"read"`
+	ShortRead     DelayRange `yaml:"short_read" json:"short_read"`
+	ScrollWait    DelayRange `yaml:"scroll_wait" json:"scroll_wait"`
+	PostScroll    DelayRange `yaml:"post_scroll" json:"post_scroll"`
+	BatchInterval DelayRange `yaml:"batch_interval" json:"batch_interval"` // 批量互动两次操作之间
+	PageLoad      DelayRange `yaml:"page_load" json:"page_load"`           // 打开页面后等待渲染
+	StepPause     DelayRange `yaml:"step_pause" json:"step_pause"`         // 填写评论等多步操作的两步之间
+	ActionSettle  DelayRange `yaml:"action_settle" json:"action_settle"`   // 点击按钮后等待页面响应，再检查结果
+}
+
+// DelayRange 随机延迟的范围
+type DelayRange struct {
+	Min Duration `yaml:"min" json:"min"`
+	Max Duration `yaml:"max" json:"max"`
+}
+
+// RateLimitsConfig 各类写操作的频率限制和每日配额，按账号分别计数
+type RateLimitsConfig struct {
+	Publish   RateLimitRule `yaml:"publish" json:"publish"`
+	SaveDraft RateLimitRule `yaml:"save_draft" json:"save_draft"`
+	Comment   RateLimitRule `yaml:"comment" json:"comment"`
+	Reply     RateLimitRule `yaml:"reply" json:"reply"`
+	Like      RateLimitRule `yaml:"like" json:"like"`
+	Favorite  RateLimitRule `yaml:"favorite" json:"favorite"`
+	Follow    RateLimitRule `yaml:"follow" json:"follow"`
+}
+
+// RateLimitRule 单类操作的限制：每分钟补充 PerMinute 次额度，最多累积 Burst 次；每天最多 Daily 次。
+// PerMinute 为 0 表示不限制频率，Daily 为 0 表示不限制每日次数。
+type RateLimitRule struct {
+	PerMinute float64 `yaml:"per_minute" json:"per_minute"`
+	Burst     int     `yaml:"burst" json:"burst"`
+	Daily     int     `yaml:"daily" json:"daily"`
+}
+
+// ScheduleConfig 定时发布的调度与重试
+type ScheduleConfig struct {
+	PollInterval Duration `yaml:"poll_interval" json:"poll_interval"` // 扫描到期任务的间隔
+	RetryDelay   Duration `yaml:"retry_delay" json:"retry_delay"`     // 失败后的重试间隔，第 n 次失败后等待 n 倍
+	MaxAttempts  int      `yaml:"max_attempts" json:"max_attempts"`   // 每个任务最多尝试的次数
+}
+
+// StorageConfig 本地文件路径，为空时使用各模块的默认路径
+type StorageConfig struct {
+	CookiesPath    string `yaml:"cookies_path" json:"cookies_path" env:"COOKIES_PATH"`
+	AccountsPath   string `yaml:"accounts_path" json:"accounts_path" env:"ACCOUNTS_PATH"`
+	ScheduleDBPath string `yaml:"schedule_db_path" json:"schedule_db_path" env:"SCHEDULE_DB_PATH"`
+	AuditLogPath   string `yaml:"audit_log_path" json:"audit_log_path" env:"AUDIT_LOG_PATH"`
+	ArtifactsDir   string `yaml:"artifacts_dir" json:"artifacts_dir" env:"DEBUG_ARTIFACTS_DIR"`
+	SelectorsPath  string `yaml:"selectors_path" json:"selectors_path" env:"SELECTORS_PATH"`
+	ImagesDir      string `yaml:"images_dir" json:"images_dir"` // 下载网络图片的目录
+}
+
+// FeaturesConfig 功能开关
+type FeaturesConfig struct {
+	RateLimit      bool `yaml:"rate_limit" json:"rate_limit"`           // 写操作限流
+	AuditLog       bool `yaml:"audit_log" json:"audit_log"`             // 写操作审计日志
+	DebugArtifacts bool `yaml:"debug_artifacts" json:"debug_artifacts"` // 失败时保存页面快照
+}
+
+// Duration 配置中的时长，使用 "300ms"、"5m" 这样的格式
+type Duration struct {
+	time.Duration
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(strings.TrimSpace(string(text)))
+	if err != nil {
+		return err
+	}
+	d.Duration = v
+	return nil
+}
+
+func seconds(n int) Duration {
+	return Duration{time.Duration(n) * time.Second}
+}
+
+func millis(min, max int) DelayRange {
+	return DelayRange{
+		Min: Duration{time.Duration(min) * time.Millisecond},
+		Max: Duration{time.Duration(max) * time.Millisecond},
+	}
+}
+
+// Default 返回默认配置
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Transport:       TransportHTTP,
+			Addr:            ":18060",
+			ShutdownTimeout: seconds(5),
+		},
+		Browser: BrowserConfig{
+			Headless:        true,
+			PoolSize:        2,
+			PoolIdleTimeout: seconds(10 * 60),
+		},
+		Timeouts: TimeoutsConfig{
+			PageAction:     seconds(60),
+			ChannelFeeds:   seconds(5 * 60),
+			Upload:         seconds(300),
+			ImageUpload:    seconds(60),
+			VideoProcess:   seconds(5 * 60),
+			PublishConfirm: seconds(60),
+			ElementWait:    seconds(15),
+			ElementProbe:   seconds(5),
+			EditorLoad:     seconds(30),
+			FeedDetail:     seconds(10 * 60),
+			ReplyComment:   seconds(5 * 60),
+			LoginWait:      seconds(4 * 60),
+			Snapshot:       seconds(30),
+		},
+		Delays: DelaysConfig{
+			Human:         millis(300, 700),
+			Reaction:      millis(300, 800),
+			Hover:         millis(100, 300),
+			Read:          millis(500, 1200),
+			ShortRead:     millis(600, 1200),
+			ScrollWait:    millis(100, 200),
+			PostScroll:    millis(300, 500),
+			BatchInterval: millis(3000, 8000),
+			PageLoad:      millis(1000, 1000),
+			StepPause:     millis(500, 1000),
+			ActionSettle:  millis(2000, 3000),
+		},
+		RateLimits: RateLimitsConfig{
+			Publish:   RateLimitRule{PerMinute: 0.1, Burst: 2, Daily: 10},
+			SaveDraft: RateLimitRule{PerMinute: 0.5, Burst: 3, Daily: 30},
+			Comment:   RateLimitRule{PerMinute: 1, Burst: 3, Daily: 50},
+			Reply:     RateLimitRule{PerMinute: 1, Burst: 3, Daily: 50},
+			Like:      RateLimitRule{PerMinute: 6, Burst: 10, Daily: 300},
+			Favorite:  RateLimitRule{PerMinute: 6, Burst: 10, Daily: 200},
+			Follow:    RateLimitRule{PerMinute: 2, Burst: 5, Daily: 100},
+		},
+		Schedule: ScheduleConfig{
+			PollInterval: seconds(10),
+			RetryDelay:   seconds(5 * 60),
+			MaxAttempts:  3,
+		},
+		Features: FeaturesConfig{
+			RateLimit:      true,
+			AuditLog:       true,
+			DebugArtifacts: true,
+		},
+	}
+}
+
+var current atomic.Pointer[Config]
+
+func init() {
+	cfg := Default()
+	current.Store(&cfg)
+}
+
+// Get 返回当前生效的配置
+func Get() *Config {
+	return current.Load()
+}
+
+// Set 设置当前生效的配置
+func Set(cfg Config) {
+	current.Store(&cfg)
+}
+
+// GetConfigPath 获取配置文件路径，可通过环境变量 CONFIG_PATH 指定
+func GetConfigPath() string {
+	path := os.Getenv("CONFIG_PATH")
+	if path == "" {
+		path = "config.yaml"
+	}
+	return path
+}
+
+// Load 在默认值上依次应用配置文件和环境变量，配置文件不存在时忽略
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return Config{}, errors.Wrap(err, "failed to read config file")
+	}
+	if err == nil {
+		logrus.Infof("加载配置文件: %s", path)
+		if err := Parse(data, &cfg); err != nil {
+			return Config{}, err
+		}
+	}
+
+	if err := applyEnv(reflect.ValueOf(&cfg).Elem(), envPrefix, os.LookupEnv); err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Parse 将 YAML 配置覆盖到 cfg 上，只需写出要修改的配置项；未知的配置项视为错误
+func Parse(data []byte, cfg *Config) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && err != io.EOF {
+		return errors.Wrap(err, "invalid config file")
+	}
+	return nil
+}
+
+// Validate 校验配置
+func (c *Config) Validate() error {
+	if c.Server.Transport != TransportHTTP && c.Server.Transport != TransportStdio {
+		return errors.Errorf("server.transport 无效: %q，可选 http|stdio", c.Server.Transport)
+	}
+	if strings.TrimSpace(c.Server.Addr) == "" {
+		return errors.New("server.addr 不能为空")
+	}
+	if c.Browser.PoolSize < 0 {
+		return errors.New("browser.pool_size 不能为负数")
+	}
+	if c.Server.ShutdownTimeout.Duration <= 0 {
+		return errors.Errorf("server.shutdown_timeout 必须大于 0: %s", c.Server.ShutdownTimeout)
+	}
+	if c.Browser.PoolIdleTimeout.Duration <= 0 {
+		return errors.Errorf("browser.pool_idle_timeout 必须大于 0: %s", c.Browser.PoolIdleTimeout)
+	}
+
+	if c.Schedule.PollInterval.Duration <= 0 {
+		return errors.Errorf("schedule.poll_interval 必须大于 0: %s", c.Schedule.PollInterval)
+	}
+	if c.Schedule.RetryDelay.Duration <= 0 {
+		return errors.Errorf("schedule.retry_delay 必须大于 0: %s", c.Schedule.RetryDelay)
+	}
+	if c.Schedule.MaxAttempts < 1 {
+		return errors.Errorf("schedule.max_attempts 至少为 1: %d", c.Schedule.MaxAttempts)
+	}
+
+	timeouts := reflect.ValueOf(c.Timeouts)
+	for i := 0; i < timeouts.NumField(); i++ {
+		d := timeouts.Field(i).Interface().(Duration)
+		if d.Duration <= 0 {
+			name := timeouts.Type().Field(i).Tag.Get("yaml")
+			return errors.Errorf("timeouts.%s 必须大于 0: %s", name, d)
+		}
+	}
+
+	delays := reflect.ValueOf(c.Delays)
+	for i := 0; i < delays.NumField(); i++ {
+		r := delays.Field(i).Interface().(DelayRange)
+		if r.Min.Duration < 0 || r.Max.Duration < r.Min.Duration {
+			name := delays.Type().Field(i).Tag.Get("yaml")
+			return errors.Errorf("delays.%s 范围无效: min=%s, max=%s", name, r.Min, r.Max)
+		}
+	}
+
+	limits := reflect.ValueOf(c.RateLimits)
+	for i := 0; i < limits.NumField(); i++ {
+		r := limits.Field(i).Interface().(RateLimitRule)
+		if r.PerMinute < 0 || r.Burst < 0 || r.Daily < 0 {
+			name := limits.Type().Field(i).Tag.Get("yaml")
+			return errors.Errorf("rate_limits.%s 不能为负数: per_minute=%v, burst=%d, daily=%d", name, r.PerMinute, r.Burst, r.Daily)
+		}
+	}
+	return nil
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// applyEnv 用环境变量覆盖配置。
+// 每个配置项对应 XHS_ 加大写路径的变量；带 env 标签的配置项同时兼容旧的环境变量名，XHS_ 变量优先。
+func applyEnv(v reflect.Value, prefix string, lookup func(string) (string, bool)) error {
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		field := v.Field(i)
+		name := prefix + strings.ToUpper(sf.Tag.Get("yaml"))
+
+		if field.Kind() == reflect.Struct && !field.Addr().Type().Implements(textUnmarshalerType) {
+			if err := applyEnv(field, name+"_", lookup); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, key := range []string{sf.Tag.Get("env"), name} {
+			if key == "" {
+				continue
+			}
+			value, ok := lookup(key)
+			if !ok {
+				continue
+			}
+			if err := setField(field, value); err != nil {
+				return errors.Wrapf(err, "invalid environment variable %s", key)
+			}
+		}
+	}
+	return nil
+}
+
+func setField(field reflect.Value, value string) error {
+	if u, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(value))
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return errors.Errorf("unsupported config type %s", field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package configs
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	cfg := Default()
+	require.NoError(t, Parse([]byte(`
+server:
+  addr: ":8080"
+timeouts:
+  feed_detail: 3m
+delays:
+  human: {min: 100ms, max: 200ms}
+rate_limits:
+  comment: {per_minute: 0.5, burst: 1, daily: 20}
+features:
+  audit_log: false
+`), &cfg))
+
+	require.Equal(t, ":8080", cfg.Server.Addr)
+	require.Equal(t, 3*time.Minute, cfg.Timeouts.FeedDetail.Duration)
+	require.Equal(t, 100*time.Millisecond, cfg.Delays.Human.Min.Duration)
+	require.Equal(t, RateLimitRule{PerMinute: 0.5, Burst: 1, Daily: 20}, cfg.RateLimits.Comment)
+	require.Equal(t, Default().RateLimits.Like, cfg.RateLimits.Like, "未配置的操作类型沿用默认限制")
+	require.False(t, cfg.Features.AuditLog)
+	require.Equal(t, 60*time.Second, cfg.Timeouts.PageAction.Duration, "未配置的沿用默认值")
+	require.True(t, cfg.Browser.Headless)
+
+	require.Error(t, Parse([]byte("server:\n  unknown: 1\n"), &cfg))
+	require.Error(t, Parse([]byte("timeouts:\n  upload: soon\n"), &cfg))
+	require.NoError(t, Parse(nil, &cfg))
+}
+
+func TestApplyEnv(t *testing.T) {
+	env := map[string]string{
+		"XHS_SERVER_ADDR":                 ":9090",
+		"XHS_BROWSER_HEADLESS":            "false",
+		"XHS_BROWSER_POOL_SIZE":           "4",
+		"XHS_DELAYS_BATCH_INTERVAL_MAX":   "10s",
+		"XHS_RATE_LIMITS_LIKE_PER_MINUTE": "1.5",
+		"ROD_BROWSER_BIN":                 "/usr/bin/chromium",
+		"ACCOUNTS_PATH":                   "old.json",
+		"XHS_STORAGE_ACCOUNTS_PATH":       "new.json",
+	}
+	lookup := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+
+	cfg := Default()
+	require.NoError(t, applyEnv(reflect.ValueOf(&cfg).Elem(), envPrefix, lookup))
+	require.Equal(t, ":9090", cfg.Server.Addr)
+	require.False(t, cfg.Browser.Headless)
+	require.Equal(t, 4, cfg.Browser.PoolSize)
+	require.Equal(t, 10*time.Second, cfg.Delays.BatchInterval.Max.Duration)
+	require.Equal(t, 1.5, cfg.RateLimits.Like.PerMinute)
+	require.Equal(t, "/usr/bin/chromium", cfg.Browser.BinPath, "兼容旧的环境变量名")
+	require.Equal(t, "new.json", cfg.Storage.AccountsPath, "XHS_ 变量优先")
+
+	env = map[string]string{"XHS_BROWSER_POOL_SIZE": "many"}
+	require.ErrorContains(t, applyEnv(reflect.ValueOf(&cfg).Elem(), envPrefix, lookup), "XHS_BROWSER_POOL_SIZE")
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	cfg, err := Load(path)
+	require.NoError(t, err, "配置文件不存在时使用默认值")
+	require.Equal(t, Default().Server.Addr, cfg.Server.Addr)
+
+	require.NoError(t, os.WriteFile(path, []byte("delays:\n  read: {min: 2s, max: 1s}\n"), 0600))
+	_, err = Load(path)
+	require.ErrorContains(t, err, "delays.read")
+
+	require.NoError(t, os.WriteFile(path, []byte("server:\n  transport: grpc\n"), 0600))
+	_, err = Load(path)
+	require.ErrorContains(t, err, "server.transport")
+
+	require.NoError(t, os.WriteFile(path, []byte("timeouts:\n  publish_confirm: 0s\n"), 0600))
+	_, err = Load(path)
+	require.ErrorContains(t, err, "timeouts.publish_confirm")
+
+	require.NoError(t, os.WriteFile(path, []byte("server:\n  shutdown_timeout: -1s\n"), 0600))
+	_, err = Load(path)
+	require.ErrorContains(t, err, "server.shutdown_timeout")
+
+	require.NoError(t, os.WriteFile(path, []byte("rate_limits:\n  follow: {daily: -1}\n"), 0600))
+	_, err = Load(path)
+	require.ErrorContains(t, err, "rate_limits.follow")
+
+	require.NoError(t, os.WriteFile(path, []byte("schedule:\n  max_attempts: 0\n"), 0600))
+	_, err = Load(path)
+	require.ErrorContains(t, err, "schedule.max_attempts")
+}
+
+func TestConfigJSON(t *testing.T) {
+	// /api/v1/config 直接返回配置，时长以可读的格式展示
+	data, err := json.Marshal(Default())
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"page_action":"1m0s"`)
+	require.Contains(t, string(data), `"human":{"min":"300ms","max":"700ms"}`)
+}
@@ -9,6 +9,10 @@ const (
 	ImagesDir = "xiaohongshu_images"
 )
 
+// GetImagesPath 下载网络图片的目录，未配置 storage.images_dir 时使用系统临时目录
 func GetImagesPath() string {
+	if dir := Get().Storage.ImagesDir; dir != "" {
+		return dir
+	}
 	return filepath.Join(os.TempDir(), ImagesDir)
 }
@@ -74,3 +74,13 @@ func GetCookiesFilePath() string {
 	// 文件不存在，使用新路径（当前目录）
 	return path
 }
+
+// GetAccountCookiesFilePath 获取指定账号的 cookies 文件路径。
+// 账号名为空时与 GetCookiesFilePath 相同，否则在默认路径后追加 ".<account>" 后缀。
+func GetAccountCookiesFilePath(account string) string {
+	base := GetCookiesFilePath()
+	if account == "" {
+		return base
+	}
+	return base + "." + account
+}
@@ -1,6 +1,99 @@
 package errors
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var ErrNoFeeds = errors.New("没有捕获到 feeds 数据")
 var ErrNoFeedDetail = errors.New("没有捕获到 feed 详情数据")
+
+// Code 稳定的错误代码，HTTP API 和 MCP 结果中原样返回，客户端可据此分支处理
+type Code string
+
+const (
+	CodeNotLoggedIn      Code = "NOT_LOGGED_IN"
+	CodeNoteDeleted      Code = "NOTE_DELETED"
+	CodeNotePrivate      Code = "NOTE_PRIVATE"
+	CodeNoteUnavailable  Code = "NOTE_UNAVAILABLE"
+	CodeRiskControl      Code = "RISK_CONTROL"
+	CodeCaptchaRequired  Code = "CAPTCHA_REQUIRED"
+	CodeTitleTooLong     Code = "TITLE_TOO_LONG"
+	CodeContentTooLong   Code = "CONTENT_TOO_LONG"
+	CodeUploadTimeout    Code = "UPLOAD_TIMEOUT"
+	CodeSelectorNotFound Code = "SELECTOR_NOT_FOUND"
+	CodeRateLimited      Code = "RATE_LIMITED"
+	CodeInvalidRequest   Code = "INVALID_REQUEST" // 请求参数错误，由服务校验参数时返回
+)
+
+// Error 带错误代码的错误。
+// errors.Is 按错误代码匹配，因此 errors.Is(err, ErrNoteDeleted) 对任意 CodeNoteDeleted 错误都成立。
+type Error struct {
+	Code    Code
+	Message string
+	Err     error // 底层错误，可为空
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCode 实现 Coder
+func (e *Error) ErrorCode() Code {
+	return e.Code
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// 各错误代码对应的哨兵错误，用于 errors.Is 判断
+var (
+	ErrNotLoggedIn      = &Error{Code: CodeNotLoggedIn, Message: "未登录或登录已失效"}
+	ErrNoteDeleted      = &Error{Code: CodeNoteDeleted, Message: "笔记已被删除"}
+	ErrNotePrivate      = &Error{Code: CodeNotePrivate, Message: "笔记仅作者可见"}
+	ErrNoteUnavailable  = &Error{Code: CodeNoteUnavailable, Message: "笔记不可访问"}
+	ErrRiskControl      = &Error{Code: CodeRiskControl, Message: "触发平台风控（访问频繁）"}
+	ErrCaptchaRequired  = &Error{Code: CodeCaptchaRequired, Message: "需要人工完成验证码"}
+	ErrTitleTooLong     = &Error{Code: CodeTitleTooLong, Message: "标题长度超过限制"}
+	ErrContentTooLong   = &Error{Code: CodeContentTooLong, Message: "正文长度超过限制"}
+	ErrUploadTimeout    = &Error{Code: CodeUploadTimeout, Message: "上传超时"}
+	ErrSelectorNotFound = &Error{Code: CodeSelectorNotFound, Message: "页面元素未找到"}
+)
+
+// New 创建指定代码的错误
+func New(code Code, format string, args ...any) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Wrap 为底层错误附加错误代码
+func Wrap(code Code, err error, format string, args ...any) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+// SelectorNotFound 页面元素未找到，selector 写入错误信息便于排查页面改版
+func SelectorNotFound(what, selector string, err error) *Error {
+	return Wrap(CodeSelectorNotFound, err, "没有找到%s (selector: %s)", what, selector)
+}
+
+// Coder 由其他包中自带错误代码的错误类型实现，如限流错误
+type Coder interface {
+	ErrorCode() Code
+}
+
+// CodeOf 返回错误链中第一个带代码的错误的代码，没有时返回空字符串
+func CodeOf(err error) Code {
+	var c Coder
+	if errors.As(err, &c) {
+		return c.ErrorCode()
+	}
+	return ""
+}
@@ -0,0 +1,29 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorCode(t *testing.T) {
+	err := New(CodeNoteDeleted, "笔记不可访问: %s", "该笔记已被删除")
+	wrapped := pkgerrors.Wrap(fmt.Errorf("获取详情失败: %w", err), "get feed detail")
+
+	require.True(t, errors.Is(wrapped, ErrNoteDeleted))
+	require.False(t, errors.Is(wrapped, ErrNotePrivate))
+	require.Equal(t, CodeNoteDeleted, CodeOf(wrapped))
+	require.Equal(t, Code(""), CodeOf(errors.New("plain")))
+}
+
+func TestWrapKeepsCause(t *testing.T) {
+	cause := errors.New("context deadline exceeded")
+	err := SelectorNotFound("关注按钮", ".follow-button", cause)
+
+	require.True(t, errors.Is(err, ErrSelectorNotFound))
+	require.True(t, errors.Is(err, cause))
+	require.Equal(t, "没有找到关注按钮 (selector: .follow-button): context deadline exceeded", err.Error())
+}
@@ -1,9 +1,22 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
-	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/artifacts"
+	"github.com/xpzouying/xiaohongshu-mcp/audit"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/jobs"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/cursor"
+	"github.com/xpzouying/xiaohongshu-mcp/ratelimit"
+	"github.com/xpzouying/xiaohongshu-mcp/schedule"
 	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 
 	"github.com/gin-gonic/gin"
@@ -12,18 +25,82 @@ import (
 
 // respondError 返回错误响应
 func respondError(c *gin.Context, statusCode int, code, message string, details any) {
-	response := ErrorResponse{
+	writeError(c, statusCode, ErrorResponse{
 		Error:   message,
 		Code:    code,
 		Details: details,
-	}
+	})
+}
 
+func writeError(c *gin.Context, statusCode int, response ErrorResponse) {
 	logrus.Errorf("%s %s %s %d", c.Request.Method, c.Request.URL.Path,
 		c.GetString("account"), statusCode)
 
 	c.JSON(statusCode, response)
 }
 
+// respondRateLimited 写操作被限流时返回 429 及重试时间，返回是否已处理
+func respondRateLimited(c *gin.Context, err error) bool {
+	var limitErr *ratelimit.LimitError
+	if !errors.As(err, &limitErr) {
+		return false
+	}
+
+	c.Header("Retry-After", strconv.Itoa(limitErr.RetryAfterSeconds()))
+	respondError(c, http.StatusTooManyRequests, string(myerrors.CodeRateLimited),
+		"操作过于频繁，已被限流", newRateLimitDetails(limitErr))
+	return true
+}
+
+// errorCodeStatus 带错误代码的业务错误对应的 HTTP 状态码
+var errorCodeStatus = map[myerrors.Code]int{
+	myerrors.CodeNotLoggedIn:      http.StatusUnauthorized,
+	myerrors.CodeNoteDeleted:      http.StatusNotFound,
+	myerrors.CodeNotePrivate:      http.StatusForbidden,
+	myerrors.CodeNoteUnavailable:  http.StatusForbidden,
+	myerrors.CodeRiskControl:      http.StatusTooManyRequests,
+	myerrors.CodeCaptchaRequired:  http.StatusLocked,
+	myerrors.CodeTitleTooLong:     http.StatusBadRequest,
+	myerrors.CodeContentTooLong:   http.StatusBadRequest,
+	myerrors.CodeUploadTimeout:    http.StatusGatewayTimeout,
+	myerrors.CodeSelectorNotFound: http.StatusBadGateway,
+	myerrors.CodeRateLimited:      http.StatusTooManyRequests,
+	myerrors.CodeInvalidRequest:   http.StatusBadRequest,
+}
+
+// respondServiceError 返回服务调用的错误。
+// 限流和带错误代码的错误返回对应的状态码和代码，其他错误使用调用方给出的状态码和代码。
+func respondServiceError(c *gin.Context, statusCode int, code, message string, err error) {
+	if respondRateLimited(c, err) {
+		return
+	}
+
+	if errCode := myerrors.CodeOf(err); errCode != "" {
+		if status, ok := errorCodeStatus[errCode]; ok {
+			statusCode = status
+		}
+		code = string(errCode)
+	}
+
+	writeError(c, statusCode, ErrorResponse{
+		Error:      message,
+		Code:       code,
+		Details:    err.Error(),
+		ArtifactID: artifacts.IDOf(err),
+	})
+}
+
+func newRateLimitDetails(err *ratelimit.LimitError) RateLimitDetails {
+	return RateLimitDetails{
+		Account:    err.Account,
+		Action:     err.Action,
+		Reason:     err.Reason,
+		RetryAfter: err.RetryAfterSeconds(),
+		RetryAt:    time.Now().Add(err.RetryAfter).Truncate(time.Second),
+		Message:    err.Error(),
+	}
+}
+
 // respondSuccess 返回成功响应
 func respondSuccess(c *gin.Context, data any, message string) {
 	response := SuccessResponse{
@@ -42,12 +119,11 @@ func respondSuccess(c *gin.Context, data any, message string) {
 func (s *AppServer) checkLoginStatusHandler(c *gin.Context) {
 	status, err := s.xiaohongshuService.CheckLoginStatus(c.Request.Context())
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "STATUS_CHECK_FAILED",
-			"检查登录状态失败", err.Error())
+		respondServiceError(c, http.StatusInternalServerError, "STATUS_CHECK_FAILED",
+			"检查登录状态失败", err)
 		return
 	}
 
-	c.Set("account", "ai-report")
 	respondSuccess(c, status, "检查登录状态成功")
 }
 
@@ -56,8 +132,8 @@ func (s *AppServer) checkLoginStatusHandler(c *gin.Context) {
 func (s *AppServer) getLoginQrcodeHandler(c *gin.Context) {
 	result, err := s.xiaohongshuService.GetLoginQrcode(c.Request.Context())
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "STATUS_CHECK_FAILED",
-			"获取登录二维码失败", err.Error())
+		respondServiceError(c, http.StatusInternalServerError, "STATUS_CHECK_FAILED",
+			"获取登录二维码失败", err)
 		return
 	}
 
@@ -68,12 +144,12 @@ func (s *AppServer) getLoginQrcodeHandler(c *gin.Context) {
 func (s *AppServer) deleteCookiesHandler(c *gin.Context) {
 	err := s.xiaohongshuService.DeleteCookies(c.Request.Context())
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "DELETE_COOKIES_FAILED",
-			"删除 cookies 失败", err.Error())
+		respondServiceError(c, http.StatusInternalServerError, "DELETE_COOKIES_FAILED",
+			"删除 cookies 失败", err)
 		return
 	}
 
-	cookiePath := cookies.GetCookiesFilePath()
+	cookiePath := s.xiaohongshuService.CookiesFilePath(c.Request.Context())
 	respondSuccess(c, map[string]interface{}{
 		"cookie_path": cookiePath,
 		"message":     "Cookies 已成功删除，登录状态已重置。下次操作时需要重新登录。",
@@ -89,15 +165,28 @@ func (s *AppServer) publishHandler(c *gin.Context) {
 		return
 	}
 
-	// 执行发布
-	result, err := s.xiaohongshuService.PublishContent(c.Request.Context(), &req)
+	// 指定了发布时间时加入定时队列，通过 /schedules/{id} 查询
+	if req.PublishAt != nil {
+		task, err := s.xiaohongshuService.SchedulePublish(c.Request.Context(), &req)
+		if err != nil {
+			respondServiceError(c, http.StatusBadRequest, "INVALID_REQUEST",
+				"请求参数错误", err)
+			return
+		}
+
+		respondSuccess(c, task, "已加入定时发布队列")
+		return
+	}
+
+	// 提交发布任务，通过 /jobs/{id} 查询进度
+	job, err := s.xiaohongshuService.PublishContentAsync(c.Request.Context(), &req)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "PUBLISH_FAILED",
-			"发布失败", err.Error())
+		respondServiceError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err)
 		return
 	}
 
-	respondSuccess(c, result, "发布成功")
+	respondSuccess(c, job, "发布任务已提交")
 }
 
 // publishVideoHandler 发布视频内容
@@ -109,28 +198,123 @@ func (s *AppServer) publishVideoHandler(c *gin.Context) {
 		return
 	}
 
-	// 执行视频发布
-	result, err := s.xiaohongshuService.PublishVideo(c.Request.Context(), &req)
+	// 指定了发布时间时加入定时队列，通过 /schedules/{id} 查询
+	if req.PublishAt != nil {
+		task, err := s.xiaohongshuService.SchedulePublishVideo(c.Request.Context(), &req)
+		if err != nil {
+			respondServiceError(c, http.StatusBadRequest, "INVALID_REQUEST",
+				"请求参数错误", err)
+			return
+		}
+
+		respondSuccess(c, task, "已加入定时发布队列")
+		return
+	}
+
+	// 提交视频发布任务，通过 /jobs/{id} 查询进度
+	job, err := s.xiaohongshuService.PublishVideoAsync(c.Request.Context(), &req)
+	if err != nil {
+		respondServiceError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err)
+		return
+	}
+
+	respondSuccess(c, job, "视频发布任务已提交")
+}
+
+// getJobHandler 查询异步任务状态
+func (s *AppServer) getJobHandler(c *gin.Context) {
+	job, err := s.xiaohongshuService.GetJob(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusNotFound, "JOB_NOT_FOUND",
+			"任务不存在", err.Error())
+		return
+	}
+
+	respondSuccess(c, job, "获取任务状态成功")
+}
+
+// cancelJobHandler 取消异步任务
+func (s *AppServer) cancelJobHandler(c *gin.Context) {
+	job, err := s.xiaohongshuService.CancelJob(c.Param("id"))
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "PUBLISH_VIDEO_FAILED",
-			"视频发布失败", err.Error())
+		status := http.StatusNotFound
+		if errors.Is(err, jobs.ErrJobFinished) {
+			status = http.StatusConflict
+		}
+		respondError(c, status, "CANCEL_JOB_FAILED",
+			"取消任务失败", err.Error())
 		return
 	}
 
-	respondSuccess(c, result, "视频发布成功")
+	respondSuccess(c, job, "任务已取消")
+}
+
+// publishContentHandler 简化的发布文章接口
+func (s *AppServer) publishContentHandler(c *gin.Context) {
+	var req PublishContentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	result, err := s.xiaohongshuService.PublishContent(c.Request.Context(), &PublishRequest{
+		Title:   req.Title,
+		Content: req.Content,
+		Images:  req.Images,
+		Tags:    req.Tags,
+	})
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, "PUBLISH_FAILED",
+			"发布失败", err)
+		return
+	}
+
+	respondSuccess(c, PublishContentResponse{
+		Success: true,
+		PostID:  result.PostID,
+		URL:     result.URL,
+		Title:   result.Title,
+		Status:  "published",
+		Message: "文章发布成功",
+	}, "文章发布成功")
 }
 
 // listFeedsHandler 获取Feeds列表
 func (s *AppServer) listFeedsHandler(c *gin.Context) {
+	opts := ListFeedsOptions{
+		Channel: c.Query("channel"),
+		Cursor:  c.Query("cursor"),
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+				"请求参数错误", "limit 必须是非负整数")
+			return
+		}
+		opts.Limit = limit
+	}
+	if _, err := xiaohongshu.ParseFeedChannel(opts.Channel); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
 	// 获取 Feeds 列表
-	result, err := s.xiaohongshuService.ListFeeds(c.Request.Context())
-	if err != nil {
-		respondError(c, http.StatusInternalServerError, "LIST_FEEDS_FAILED",
+	result, err := s.xiaohongshuService.ListFeeds(c.Request.Context(), opts)
+	if errors.Is(err, cursor.ErrInvalidCursor) {
+		respondError(c, http.StatusBadRequest, "INVALID_CURSOR",
 			"获取Feeds列表失败", err.Error())
 		return
 	}
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, "LIST_FEEDS_FAILED",
+			"获取Feeds列表失败", err)
+		return
+	}
 
-	c.Set("account", "ai-report")
 	respondSuccess(c, result, "获取Feeds列表成功")
 }
 
@@ -138,6 +322,7 @@ func (s *AppServer) listFeedsHandler(c *gin.Context) {
 func (s *AppServer) searchFeedsHandler(c *gin.Context) {
 	var keyword string
 	var filters xiaohongshu.FilterOption
+	var opts xiaohongshu.PageOptions
 
 	switch c.Request.Method {
 	case http.MethodPost:
@@ -150,8 +335,22 @@ func (s *AppServer) searchFeedsHandler(c *gin.Context) {
 		}
 		keyword = searchReq.Keyword
 		filters = searchReq.Filters
+		opts = xiaohongshu.PageOptions{Limit: searchReq.Limit, Page: searchReq.Page}
 	default:
 		keyword = c.Query("keyword")
+
+		var err error
+		if opts, err = pageOptionsFromQuery(c); err != nil {
+			respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+				"请求参数错误", err.Error())
+			return
+		}
+	}
+
+	if err := opts.Validate(); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
 	}
 
 	if keyword == "" {
@@ -161,17 +360,38 @@ func (s *AppServer) searchFeedsHandler(c *gin.Context) {
 	}
 
 	// 搜索 Feeds
-	result, err := s.xiaohongshuService.SearchFeeds(c.Request.Context(), keyword, filters)
+	result, err := s.xiaohongshuService.SearchFeeds(c.Request.Context(), keyword, opts, filters)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "SEARCH_FEEDS_FAILED",
-			"搜索Feeds失败", err.Error())
+		respondServiceError(c, http.StatusInternalServerError, "SEARCH_FEEDS_FAILED",
+			"搜索Feeds失败", err)
 		return
 	}
 
-	c.Set("account", "ai-report")
 	respondSuccess(c, result, "搜索Feeds成功")
 }
 
+// pageOptionsFromQuery 从 query 参数 limit、page 解析分页选项
+func pageOptionsFromQuery(c *gin.Context) (xiaohongshu.PageOptions, error) {
+	var opts xiaohongshu.PageOptions
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return opts, errors.New("limit 必须是整数")
+		}
+		opts.Limit = limit
+	}
+	if v := c.Query("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil {
+			return opts, errors.New("page 必须是整数")
+		}
+		opts.Page = page
+	}
+
+	return opts, nil
+}
+
 // getFeedDetailHandler 获取Feed详情
 func (s *AppServer) getFeedDetailHandler(c *gin.Context) {
 	var req FeedDetailRequest
@@ -199,12 +419,11 @@ func (s *AppServer) getFeedDetailHandler(c *gin.Context) {
 	}
 
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "GET_FEED_DETAIL_FAILED",
-			"获取Feed详情失败", err.Error())
+		respondServiceError(c, http.StatusInternalServerError, "GET_FEED_DETAIL_FAILED",
+			"获取Feed详情失败", err)
 		return
 	}
 
-	c.Set("account", "ai-report")
 	respondSuccess(c, result, "获取Feed详情成功")
 }
 
@@ -217,18 +436,203 @@ func (s *AppServer) userProfileHandler(c *gin.Context) {
 		return
 	}
 
-	// 获取用户信息
-	result, err := s.xiaohongshuService.UserProfile(c.Request.Context(), req.UserID, req.XsecToken)
+	tab, err := xiaohongshu.ParseProfileTab(req.Tab)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "GET_USER_PROFILE_FAILED",
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+	opts := xiaohongshu.PageOptions{Limit: req.Limit, Page: req.Page}
+	if err := opts.Validate(); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	// 获取用户信息
+	result, err := s.xiaohongshuService.UserProfile(c.Request.Context(), req.UserID, req.XsecToken, tab, opts)
+	if errors.Is(err, xiaohongshu.ErrProfileTabHidden) {
+		respondError(c, http.StatusNotFound, "PROFILE_TAB_HIDDEN",
 			"获取用户主页失败", err.Error())
 		return
 	}
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, "GET_USER_PROFILE_FAILED",
+			"获取用户主页失败", err)
+		return
+	}
 
-	c.Set("account", "ai-report")
 	respondSuccess(c, map[string]any{"data": result}, "result.Message")
 }
 
+// feedActionFunc 点赞/收藏等笔记互动的服务方法
+type feedActionFunc func(ctx context.Context, feedID, xsecToken string) (*ActionResult, error)
+
+// likeFeedHandler 点赞笔记
+func (s *AppServer) likeFeedHandler(c *gin.Context) {
+	s.handleFeedAction(c, s.xiaohongshuService.LikeFeed, "LIKE_FEED_FAILED", "点赞失败")
+}
+
+// unlikeFeedHandler 取消点赞笔记
+func (s *AppServer) unlikeFeedHandler(c *gin.Context) {
+	s.handleFeedAction(c, s.xiaohongshuService.UnlikeFeed, "UNLIKE_FEED_FAILED", "取消点赞失败")
+}
+
+// favoriteFeedHandler 收藏笔记
+func (s *AppServer) favoriteFeedHandler(c *gin.Context) {
+	s.handleFeedAction(c, s.xiaohongshuService.FavoriteFeed, "FAVORITE_FEED_FAILED", "收藏失败")
+}
+
+// unfavoriteFeedHandler 取消收藏笔记
+func (s *AppServer) unfavoriteFeedHandler(c *gin.Context) {
+	s.handleFeedAction(c, s.xiaohongshuService.UnfavoriteFeed, "UNFAVORITE_FEED_FAILED", "取消收藏失败")
+}
+
+// batchLikeFeedHandler 批量点赞笔记
+func (s *AppServer) batchLikeFeedHandler(c *gin.Context) {
+	s.handleBatchFeedAction(c, batchActionLike)
+}
+
+// batchUnlikeFeedHandler 批量取消点赞笔记
+func (s *AppServer) batchUnlikeFeedHandler(c *gin.Context) {
+	s.handleBatchFeedAction(c, batchActionUnlike)
+}
+
+// batchFavoriteFeedHandler 批量收藏笔记
+func (s *AppServer) batchFavoriteFeedHandler(c *gin.Context) {
+	s.handleBatchFeedAction(c, batchActionFavorite)
+}
+
+// batchUnfavoriteFeedHandler 批量取消收藏笔记
+func (s *AppServer) batchUnfavoriteFeedHandler(c *gin.Context) {
+	s.handleBatchFeedAction(c, batchActionUnfavorite)
+}
+
+func (s *AppServer) handleFeedAction(c *gin.Context, do feedActionFunc, errCode, errMessage string) {
+	var req FeedActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	result, err := do(c.Request.Context(), req.FeedID, req.XsecToken)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, errCode,
+			errMessage, err)
+		return
+	}
+
+	respondSuccess(c, result, result.Message)
+}
+
+// handleBatchFeedAction 在同一个页面上依次处理每条笔记，单条失败不影响其他笔记
+func (s *AppServer) handleBatchFeedAction(c *gin.Context, action string) {
+	var req BatchFeedActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	ops := make([]BatchOperation, 0, len(req.Feeds))
+	for _, feed := range req.Feeds {
+		ops = append(ops, BatchOperation{Action: action, FeedID: feed.FeedID, XsecToken: feed.XsecToken})
+	}
+
+	result, err := s.xiaohongshuService.BatchInteract(c.Request.Context(), ops)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, "BATCH_INTERACT_FAILED",
+			"批量操作失败", err)
+		return
+	}
+
+	response := BatchFeedActionResponse{Results: make([]BatchFeedActionResult, 0, len(result.Results))}
+	for _, item := range result.Results {
+		success := item.Status == batchStatusSucceeded
+		if success {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+		response.Results = append(response.Results, BatchFeedActionResult{
+			FeedID:     item.FeedID,
+			Success:    success,
+			Message:    item.Message,
+			Error:      item.Error,
+			Code:       item.Code,
+			RetryAfter: item.RetryAfter,
+		})
+	}
+
+	respondSuccess(c, response, fmt.Sprintf("批量操作完成: 成功 %d 条，失败 %d 条", response.Succeeded, response.Failed))
+}
+
+// batchInteractHandler 批量执行点赞、收藏、评论、关注等操作
+func (s *AppServer) batchInteractHandler(c *gin.Context) {
+	var req BatchInteractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	result, err := s.xiaohongshuService.BatchInteract(c.Request.Context(), req.Operations)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, "BATCH_INTERACT_FAILED",
+			"批量操作失败", err)
+		return
+	}
+
+	respondSuccess(c, result, fmt.Sprintf("批量操作完成: 成功 %d 项，失败 %d 项，跳过 %d 项",
+		result.Succeeded, result.Failed, result.Skipped))
+}
+
+// followUserHandler 关注用户
+func (s *AppServer) followUserHandler(c *gin.Context) {
+	var req FollowUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	result, err := s.xiaohongshuService.FollowUser(c.Request.Context(), req.UserID, req.XsecToken)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, "FOLLOW_USER_FAILED",
+			"关注用户失败", err)
+		return
+	}
+
+	respondSuccess(c, result, followMessage(result, "关注成功", "已关注，无需重复操作"))
+}
+
+// unfollowUserHandler 取消关注用户
+func (s *AppServer) unfollowUserHandler(c *gin.Context) {
+	var req FollowUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	result, err := s.xiaohongshuService.UnfollowUser(c.Request.Context(), req.UserID, req.XsecToken)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, "UNFOLLOW_USER_FAILED",
+			"取消关注失败", err)
+		return
+	}
+
+	respondSuccess(c, result, followMessage(result, "取消关注成功", "未关注，无需取消"))
+}
+
+func followMessage(result *xiaohongshu.FollowResult, changed, unchanged string) string {
+	if result.Changed {
+		return changed
+	}
+	return unchanged
+}
+
 // postCommentHandler 发表评论到Feed
 func (s *AppServer) postCommentHandler(c *gin.Context) {
 	var req PostCommentRequest
@@ -241,12 +645,11 @@ func (s *AppServer) postCommentHandler(c *gin.Context) {
 	// 发表评论
 	result, err := s.xiaohongshuService.PostCommentToFeed(c.Request.Context(), req.FeedID, req.XsecToken, req.Content)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "POST_COMMENT_FAILED",
-			"发表评论失败", err.Error())
+		respondServiceError(c, http.StatusInternalServerError, "POST_COMMENT_FAILED",
+			"发表评论失败", err)
 		return
 	}
 
-	c.Set("account", "ai-report")
 	respondSuccess(c, result, result.Message)
 }
 
@@ -261,12 +664,11 @@ func (s *AppServer) replyCommentHandler(c *gin.Context) {
 
 	result, err := s.xiaohongshuService.ReplyCommentToFeed(c.Request.Context(), req.FeedID, req.XsecToken, req.CommentID, req.UserID, req.Content)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "REPLY_COMMENT_FAILED",
-			"回复评论失败", err.Error())
+		respondServiceError(c, http.StatusInternalServerError, "REPLY_COMMENT_FAILED",
+			"回复评论失败", err)
 		return
 	}
 
-	c.Set("account", "ai-report")
 	respondSuccess(c, result, result.Message)
 }
 
@@ -279,16 +681,407 @@ func healthHandler(c *gin.Context) {
 	})
 }
 
+// getConfigHandler 获取当前生效的配置
+func (s *AppServer) getConfigHandler(c *gin.Context) {
+	respondSuccess(c, configs.Get(), "获取配置成功")
+}
+
 // myProfileHandler 我的信息
 func (s *AppServer) myProfileHandler(c *gin.Context) {
 	// 获取当前登录用户信息
 	result, err := s.xiaohongshuService.GetMyProfile(c.Request.Context())
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "GET_MY_PROFILE_FAILED",
-			"获取我的主页失败", err.Error())
+		respondServiceError(c, http.StatusInternalServerError, "GET_MY_PROFILE_FAILED",
+			"获取我的主页失败", err)
 		return
 	}
 
-	c.Set("account", "ai-report")
 	respondSuccess(c, map[string]any{"data": result}, "获取我的主页成功")
 }
+
+// saveDraftHandler 保存图文草稿
+func (s *AppServer) saveDraftHandler(c *gin.Context) {
+	var req PublishRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	job, err := s.xiaohongshuService.SaveDraftAsync(c.Request.Context(), &req)
+	if err != nil {
+		respondServiceError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err)
+		return
+	}
+
+	respondSuccess(c, job, "保存草稿任务已提交")
+}
+
+// saveVideoDraftHandler 保存视频草稿
+func (s *AppServer) saveVideoDraftHandler(c *gin.Context) {
+	var req PublishVideoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	job, err := s.xiaohongshuService.SaveVideoDraftAsync(c.Request.Context(), &req)
+	if err != nil {
+		respondServiceError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err)
+		return
+	}
+
+	respondSuccess(c, job, "保存视频草稿任务已提交")
+}
+
+// listDraftsHandler 列出草稿箱中的草稿
+func (s *AppServer) listDraftsHandler(c *gin.Context) {
+	typ, err := xiaohongshu.ParseDraftType(c.Query("type"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	result, err := s.xiaohongshuService.ListDrafts(c.Request.Context(), typ)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, "LIST_DRAFTS_FAILED",
+			"获取草稿列表失败", err)
+		return
+	}
+
+	respondSuccess(c, result, "获取草稿列表成功")
+}
+
+// openDraftHandler 打开草稿，返回草稿内容
+func (s *AppServer) openDraftHandler(c *gin.Context) {
+	ref, err := parseDraftRef(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+	ref.Title = c.Query("title")
+
+	result, err := s.xiaohongshuService.OpenDraft(c.Request.Context(), ref)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, "OPEN_DRAFT_FAILED",
+			"打开草稿失败", err)
+		return
+	}
+
+	respondSuccess(c, result, "打开草稿成功")
+}
+
+// publishDraftHandler 发布草稿
+func (s *AppServer) publishDraftHandler(c *gin.Context) {
+	ref, err := parseDraftRef(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	// 请求体可选，用于校验草稿标题
+	var req PublishDraftRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+				"请求参数错误", err.Error())
+			return
+		}
+	}
+	ref.Title = req.Title
+
+	job, err := s.xiaohongshuService.PublishDraftAsync(c.Request.Context(), ref)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, "PUBLISH_DRAFT_FAILED",
+			"发布草稿失败", err)
+		return
+	}
+
+	respondSuccess(c, job, "发布草稿任务已提交")
+}
+
+// parseDraftRef 从路径参数解析草稿位置
+func parseDraftRef(c *gin.Context) (xiaohongshu.DraftRef, error) {
+	typ, err := xiaohongshu.ParseDraftType(c.Param("type"))
+	if err != nil {
+		return xiaohongshu.DraftRef{}, err
+	}
+
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil || index < 0 {
+		return xiaohongshu.DraftRef{}, errors.New("草稿位置必须是非负整数")
+	}
+
+	return xiaohongshu.DraftRef{Type: typ, Index: index}, nil
+}
+
+// listSchedulesHandler 列出定时发布任务，可通过 status 参数筛选
+func (s *AppServer) listSchedulesHandler(c *gin.Context) {
+	tasks, err := s.xiaohongshuService.ListScheduled(schedule.Status(c.Query("status")))
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, "LIST_SCHEDULES_FAILED",
+			"获取定时任务失败", err)
+		return
+	}
+
+	respondSuccess(c, SchedulesListResponse{Tasks: tasks, Count: len(tasks)}, "获取定时任务成功")
+}
+
+// getScheduleHandler 查询定时发布任务
+func (s *AppServer) getScheduleHandler(c *gin.Context) {
+	task, err := s.xiaohongshuService.GetScheduled(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusNotFound, "SCHEDULE_NOT_FOUND",
+			"定时任务不存在", err.Error())
+		return
+	}
+
+	respondSuccess(c, task, "获取定时任务成功")
+}
+
+// rescheduleHandler 修改定时发布时间
+func (s *AppServer) rescheduleHandler(c *gin.Context) {
+	var req RescheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	task, err := s.xiaohongshuService.RescheduleTask(c.Param("id"), req.PublishAt)
+	if err != nil {
+		respondError(c, scheduleErrorStatus(err), "RESCHEDULE_FAILED",
+			"修改定时任务失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, task, "定时任务已改期")
+}
+
+// cancelScheduleHandler 取消定时发布任务
+func (s *AppServer) cancelScheduleHandler(c *gin.Context) {
+	task, err := s.xiaohongshuService.CancelScheduled(c.Param("id"))
+	if err != nil {
+		respondError(c, scheduleErrorStatus(err), "CANCEL_SCHEDULE_FAILED",
+			"取消定时任务失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, task, "定时任务已取消")
+}
+
+// scheduleErrorStatus 定时任务错误对应的 HTTP 状态码
+func scheduleErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, schedule.ErrTaskNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, schedule.ErrPastTime):
+		return http.StatusBadRequest
+	case errors.Is(err, schedule.ErrTaskFinished), errors.Is(err, schedule.ErrTaskRunning):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// listAccountsHandler 列出所有账号
+func (s *AppServer) listAccountsHandler(c *gin.Context) {
+	list := s.accounts.List()
+	respondSuccess(c, AccountsListResponse{Accounts: list, Count: len(list)}, "获取账号列表成功")
+}
+
+// addAccountHandler 新增账号
+func (s *AppServer) addAccountHandler(c *gin.Context) {
+	var req AddAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	account, err := s.accounts.Add(req.Name)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, accounts.ErrAccountExists) {
+			status = http.StatusConflict
+		} else if errors.Is(err, accounts.ErrInvalidName) {
+			status = http.StatusBadRequest
+		}
+		respondError(c, status, "ADD_ACCOUNT_FAILED",
+			"新增账号失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, account, "新增账号成功，请使用该账号获取登录二维码完成登录")
+}
+
+// removeAccountHandler 删除账号
+func (s *AppServer) removeAccountHandler(c *gin.Context) {
+	name := c.Param("name")
+	if err := s.accounts.Remove(name); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, accounts.ErrAccountNotFound) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, accounts.ErrDefaultAccount) {
+			status = http.StatusBadRequest
+		}
+		respondError(c, status, "REMOVE_ACCOUNT_FAILED",
+			"删除账号失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, map[string]any{"name": name}, "删除账号成功")
+}
+
+// getAccountChallengeHandler 获取账号遇到的验证码，包括检测时间、页面地址和截图
+func (s *AppServer) getAccountChallengeHandler(c *gin.Context) {
+	challenge, ok := s.accountChallenge(c)
+	if !ok {
+		return
+	}
+
+	respondSuccess(c, challenge, "账号遇到验证码，需要人工处理")
+}
+
+// getAccountChallengeScreenshotHandler 直接返回验证码页面的 PNG 截图，便于在浏览器中查看
+func (s *AppServer) getAccountChallengeScreenshotHandler(c *gin.Context) {
+	challenge, ok := s.accountChallenge(c)
+	if !ok {
+		return
+	}
+	if len(challenge.Screenshot) == 0 {
+		respondError(c, http.StatusNotFound, "SCREENSHOT_NOT_FOUND",
+			"验证码页面没有截图", nil)
+		return
+	}
+
+	c.Data(http.StatusOK, "image/png", challenge.Screenshot)
+}
+
+func (s *AppServer) accountChallenge(c *gin.Context) (*AccountChallengeResponse, bool) {
+	account, err := s.accounts.Get(c.Param("name"))
+	if err != nil {
+		respondError(c, http.StatusNotFound, "ACCOUNT_NOT_FOUND",
+			"账号不存在", err.Error())
+		return nil, false
+	}
+
+	challenge, err := s.xiaohongshuService.GetChallenge(accounts.WithAccount(c.Request.Context(), account.Name))
+	if err != nil {
+		respondError(c, http.StatusNotFound, "NO_CHALLENGE",
+			"账号没有待处理的验证码", err.Error())
+		return nil, false
+	}
+	return challenge, true
+}
+
+// resumeAccountHandler 人工完成验证后恢复账号
+func (s *AppServer) resumeAccountHandler(c *gin.Context) {
+	name := c.Param("name")
+	if err := s.xiaohongshuService.ResumeAccount(accounts.WithAccount(c.Request.Context(), name)); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, accounts.ErrAccountNotFound) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, accounts.ErrNoChallenge) {
+			status = http.StatusConflict
+		}
+		respondError(c, status, "RESUME_ACCOUNT_FAILED",
+			"恢复账号失败", err.Error())
+		return
+	}
+
+	respondSuccess(c, map[string]any{"name": name}, "账号已恢复")
+}
+
+// getArtifactHandler 获取调试快照信息
+func (s *AppServer) getArtifactHandler(c *gin.Context) {
+	meta, err := s.xiaohongshuService.GetArtifact(c.Param("id"))
+	if err != nil {
+		respondArtifactError(c, err)
+		return
+	}
+
+	respondSuccess(c, meta, "获取调试快照成功")
+}
+
+// getArtifactFileHandler 获取调试快照中的截图或页面 HTML
+func (s *AppServer) getArtifactFileHandler(c *gin.Context) {
+	name := c.Param("file")
+	path, err := s.xiaohongshuService.ArtifactFilePath(c.Param("id"), name)
+	if err != nil {
+		respondArtifactError(c, err)
+		return
+	}
+
+	// 页面 HTML 以附件形式下载，避免在本服务的域名下执行页面中的脚本
+	if name == artifacts.FileHTML {
+		c.FileAttachment(path, c.Param("id")+".html")
+		return
+	}
+	c.File(path)
+}
+
+func respondArtifactError(c *gin.Context, err error) {
+	if errors.Is(err, artifacts.ErrNotFound) {
+		respondError(c, http.StatusNotFound, "ARTIFACT_NOT_FOUND",
+			"调试快照不存在", nil)
+		return
+	}
+	respondError(c, http.StatusInternalServerError, "GET_ARTIFACT_FAILED",
+		"获取调试快照失败", err.Error())
+}
+
+// queryAuditHandler 查询写操作审计日志。
+// 支持 since/until（RFC3339）、action、account、outcome、limit 查询参数，按时间倒序返回。
+func (s *AppServer) queryAuditHandler(c *gin.Context) {
+	filter := audit.Filter{
+		Action:  audit.Action(c.Query("action")),
+		Outcome: c.Query("outcome"),
+	}
+	// account 参数已由 accountMiddleware 校验并规范化，未指定时查询所有账号
+	if c.Query("account") != "" {
+		filter.Account = c.GetString("account")
+	}
+
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"since", &filter.Since}, {"until", &filter.Until}} {
+		v := c.Query(p.name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+				"请求参数错误", fmt.Sprintf("%s 必须是 RFC3339 格式的时间", p.name))
+			return
+		}
+		*p.dst = t
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+				"请求参数错误", "limit 必须是非负整数")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	entries, err := s.xiaohongshuService.QueryAudit(filter)
+	if err != nil {
+		respondServiceError(c, http.StatusInternalServerError, "QUERY_AUDIT_FAILED",
+			"查询审计日志失败", err)
+		return
+	}
+
+	respondSuccess(c, AuditListResponse{Entries: entries, Count: len(entries)}, "查询审计日志成功")
+}
@@ -0,0 +1,258 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/progress"
+)
+
+// Stage 任务所处阶段
+type Stage string
+
+const (
+	StagePending     Stage = "pending"
+	StageDownloading Stage = "downloading"
+	StageUploading   Stage = "uploading"
+	StageFilling     Stage = "filling"
+	StageSubmitting  Stage = "submitting"
+	StageDone        Stage = "done"
+	StageFailed      Stage = "failed"
+	StageCanceled    Stage = "canceled"
+)
+
+// finishedRetention 已结束的任务保留时长
+const finishedRetention = 24 * time.Hour
+
+var (
+	ErrJobNotFound = errors.New("任务不存在")
+	ErrJobFinished = errors.New("任务已结束")
+)
+
+// Job 任务状态快照
+type Job struct {
+	ID         string     `json:"id"`
+	Type       string     `json:"type"`
+	Account    string     `json:"account"`
+	Stage      Stage      `json:"stage"`
+	Progress   *Progress  `json:"progress,omitempty"` // 当前阶段的进度，如已上传的图片数
+	Result     any        `json:"result,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// Progress 任务进度
+type Progress struct {
+	Current int    `json:"current"`
+	Total   int    `json:"total,omitempty"` // 总数，未知时为 0
+	Message string `json:"message,omitempty"`
+}
+
+// Finished 任务是否已结束
+func (j Job) Finished() bool {
+	return j.Stage == StageDone || j.Stage == StageFailed || j.Stage == StageCanceled
+}
+
+// RunFunc 任务执行函数，通过 Report(ctx, stage) 上报阶段
+type RunFunc func(ctx context.Context) (any, error)
+
+type job struct {
+	Job
+	cancel context.CancelFunc
+}
+
+// Manager 内存中的异步任务管理器
+type Manager struct {
+	mu   sync.Mutex
+	jobs map[string]*job
+}
+
+// NewManager 创建任务管理器
+func NewManager() *Manager {
+	return &Manager{jobs: make(map[string]*job)}
+}
+
+// Submit 提交任务并立即返回。
+// 任务在独立的 context 中运行：继承 ctx 中的值（如账号），但不随请求结束而取消。
+func (m *Manager) Submit(ctx context.Context, jobType, account string, run RunFunc) Job {
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	now := time.Now()
+	j := &job{
+		Job: Job{
+			ID:        newID(),
+			Type:      jobType,
+			Account:   account,
+			Stage:     StagePending,
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		cancel: cancel,
+	}
+
+	m.mu.Lock()
+	m.cleanupLocked()
+	m.jobs[j.ID] = j
+	snapshot := j.Job
+	m.mu.Unlock()
+
+	go m.run(runCtx, j, run)
+
+	logrus.Infof("提交任务: id=%s, type=%s, account=%s", j.ID, jobType, account)
+	return snapshot
+}
+
+// Get 获取任务状态
+func (m *Manager) Get(id string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j, ok := m.jobs[id]
+	if !ok {
+		return Job{}, errors.Wrap(ErrJobNotFound, id)
+	}
+	return j.Job, nil
+}
+
+// List 返回所有任务，按创建时间倒序
+func (m *Manager) List() []Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	list := make([]Job, 0, len(m.jobs))
+	for _, j := range m.jobs {
+		list = append(list, j.Job)
+	}
+	sort.Slice(list, func(i, k int) bool { return list[i].CreatedAt.After(list[k].CreatedAt) })
+
+	return list
+}
+
+// Cancel 取消任务。已结束的任务返回 ErrJobFinished。
+func (m *Manager) Cancel(id string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j, ok := m.jobs[id]
+	if !ok {
+		return Job{}, errors.Wrap(ErrJobNotFound, id)
+	}
+	if j.Finished() {
+		return j.Job, ErrJobFinished
+	}
+
+	j.cancel()
+	m.finishLocked(j, StageCanceled, nil, "任务已取消")
+
+	logrus.Infof("取消任务: id=%s", id)
+	return j.Job, nil
+}
+
+func (m *Manager) run(ctx context.Context, j *job, run RunFunc) {
+	defer j.cancel()
+
+	ctx = context.WithValue(ctx, idKey{}, j.ID)
+	ctx = withReporter(ctx, func(stage Stage) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		if j.Finished() {
+			return
+		}
+		j.Stage = stage
+		j.UpdatedAt = time.Now()
+		logrus.Infof("任务 %s 进入阶段: %s", j.ID, stage)
+	})
+	// 覆盖提交任务的请求设置的进度回调，任务进度只记录在任务状态中。
+	// 提交任务的请求（如 MCP 工具调用）此时已经返回，按 MCP 规范不能再向它的 progressToken 发送进度通知
+	ctx = progress.WithReporter(ctx, func(current, total int, message string) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		if j.Finished() {
+			return
+		}
+		j.Progress = &Progress{Current: current, Total: total, Message: message}
+		j.UpdatedAt = time.Now()
+	})
+
+	result, err := func() (result any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = errors.Errorf("任务执行时发生内部错误: %v", r)
+			}
+		}()
+		return run(ctx)
+	}()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// 已被取消的任务保持取消状态
+	if j.Finished() {
+		return
+	}
+
+	if err != nil {
+		logrus.Errorf("任务 %s 失败: %v", j.ID, err)
+		m.finishLocked(j, StageFailed, nil, err.Error())
+		return
+	}
+
+	m.finishLocked(j, StageDone, result, "")
+}
+
+func (m *Manager) finishLocked(j *job, stage Stage, result any, errMsg string) {
+	now := time.Now()
+	j.Stage = stage
+	j.Result = result
+	j.Error = errMsg
+	j.UpdatedAt = now
+	j.FinishedAt = &now
+}
+
+// cleanupLocked 清理过期的已结束任务，调用方需持有锁
+func (m *Manager) cleanupLocked() {
+	for id, j := range m.jobs {
+		if j.FinishedAt != nil && time.Since(*j.FinishedAt) > finishedRetention {
+			delete(m.jobs, id)
+		}
+	}
+}
+
+type idKey struct{}
+
+// IDFromContext 返回 ctx 所属任务的ID，不属于任何任务时返回空字符串
+func IDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(idKey{}).(string)
+	return id
+}
+
+type reporterKey struct{}
+
+func withReporter(ctx context.Context, fn func(Stage)) context.Context {
+	return context.WithValue(ctx, reporterKey{}, fn)
+}
+
+// Report 上报当前任务阶段。ctx 不属于任何任务时不做任何事，因此同步调用也可以安全使用。
+func Report(ctx context.Context, stage Stage) {
+	if fn, ok := ctx.Value(reporterKey{}).(func(Stage)); ok {
+		fn(stage)
+	}
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return time.Now().Format("20060102150405.000000000")
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,100 @@
+package jobs
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/progress"
+)
+
+func waitFinished(t *testing.T, m *Manager, id string) Job {
+	t.Helper()
+
+	var job Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = m.Get(id)
+		require.NoError(t, err)
+		return job.Finished()
+	}, time.Second, 5*time.Millisecond)
+
+	return job
+}
+
+func TestManager(t *testing.T) {
+	m := NewManager()
+
+	done := m.Submit(context.Background(), "publish", "default", func(ctx context.Context) (any, error) {
+		Report(ctx, StageUploading)
+		progress.Report(ctx, 1, 2, "已上传 1/2 张图片")
+		return IDFromContext(ctx), nil
+	})
+	require.Equal(t, StagePending, done.Stage)
+
+	job := waitFinished(t, m, done.ID)
+	require.Equal(t, StageDone, job.Stage)
+	require.Equal(t, done.ID, job.Result)
+	require.Equal(t, &Progress{Current: 1, Total: 2, Message: "已上传 1/2 张图片"}, job.Progress)
+
+	failed := m.Submit(context.Background(), "publish", "default", func(ctx context.Context) (any, error) {
+		return nil, errors.New("boom")
+	})
+	job = waitFinished(t, m, failed.ID)
+	require.Equal(t, StageFailed, job.Stage)
+	require.Equal(t, "boom", job.Error)
+
+	_, err := m.Cancel(done.ID)
+	require.ErrorIs(t, err, ErrJobFinished)
+
+	_, err = m.Get("missing")
+	require.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestManagerKeepsProgressInJob(t *testing.T) {
+	m := NewManager()
+
+	// 提交任务的请求设置的进度回调不再收到任务的进度
+	var forwarded atomic.Bool
+	ctx := progress.WithReporter(context.Background(), func(current, total int, message string) {
+		forwarded.Store(true)
+	})
+
+	submitted := m.Submit(ctx, "publish", "default", func(ctx context.Context) (any, error) {
+		progress.Report(ctx, 1, 2, "已上传 1/2 张图片")
+		return nil, nil
+	})
+
+	job := waitFinished(t, m, submitted.ID)
+	require.Equal(t, &Progress{Current: 1, Total: 2, Message: "已上传 1/2 张图片"}, job.Progress)
+	require.False(t, forwarded.Load())
+}
+
+func TestManagerCancel(t *testing.T) {
+	m := NewManager()
+
+	started := make(chan struct{})
+	submitted := m.Submit(context.Background(), "publish", "default", func(ctx context.Context) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	<-started
+
+	job, err := m.Cancel(submitted.ID)
+	require.NoError(t, err)
+	require.Equal(t, StageCanceled, job.Stage)
+
+	// 任务函数返回后仍保持取消状态
+	time.Sleep(20 * time.Millisecond)
+	job, err = m.Get(submitted.ID)
+	require.NoError(t, err)
+	require.Equal(t, StageCanceled, job.Stage)
+}
+
+func TestReportOutsideJob(t *testing.T) {
+	require.NotPanics(t, func() { Report(context.Background(), StageFilling) })
+}
@@ -4,47 +4,187 @@ import (
 	"flag"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/artifacts"
+	"github.com/xpzouying/xiaohongshu-mcp/audit"
+	"github.com/xpzouying/xiaohongshu-mcp/browser"
 	"github.com/xpzouying/xiaohongshu-mcp/configs"
 	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	"github.com/xpzouying/xiaohongshu-mcp/ratelimit"
+	"github.com/xpzouying/xiaohongshu-mcp/schedule"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 )
 
 func main() {
 	var (
+		configPath string
+		transport  string
+
 		headless bool
 		binPath  string // 浏览器二进制文件路径
 		port     string
+
+		poolSize        int
+		poolIdleTimeout time.Duration
 	)
+	flag.StringVar(&configPath, "config", configs.GetConfigPath(), "配置文件路径")
+	flag.StringVar(&transport, "transport", configs.TransportHTTP, "MCP 传输方式：http|stdio。stdio 模式通过标准输入输出提供 MCP 服务，不启动 HTTP 服务")
 	flag.BoolVar(&headless, "headless", true, "是否无头模式")
 	flag.StringVar(&binPath, "bin", "", "浏览器二进制文件路径")
 	flag.StringVar(&port, "port", ":18060", "端口")
+	flag.IntVar(&poolSize, "pool-size", 2, "浏览器池大小，即最多同时运行的浏览器数")
+	flag.DurationVar(&poolIdleTimeout, "pool-idle-timeout", 10*time.Minute, "浏览器空闲多久后回收")
 	flag.Parse()
 
+	// 加载配置：默认值 < 配置文件 < 环境变量 < 显式指定的命令行参数
+	cfg, err := configs.Load(configPath)
+	if err != nil {
+		logrus.Fatalf("failed to load config: %v", err)
+	}
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "transport":
+			cfg.Server.Transport = transport
+		case "headless":
+			cfg.Browser.Headless = headless
+		case "bin":
+			cfg.Browser.BinPath = binPath
+		case "port":
+			cfg.Server.Addr = port
+		case "pool-size":
+			cfg.Browser.PoolSize = poolSize
+		case "pool-idle-timeout":
+			cfg.Browser.PoolIdleTimeout.Duration = poolIdleTimeout
+		}
+	})
+	if err := cfg.Validate(); err != nil {
+		logrus.Fatalf("invalid config: %v", err)
+	}
+
+	// stdio 模式下标准输出只用于传输 MCP 消息。在启动任何后台任务前保存原始的标准输入输出，
+	// 并将 os.Stdout 改指向标准错误，避免其他代码（如浏览器下载进度、定时任务）的输出破坏协议
+	stdin, stdout := os.Stdin, os.Stdout
+	if cfg.Server.Transport == configs.TransportStdio {
+		os.Stdout = os.Stderr
+	}
+
+	if cfg.Storage.CookiesPath != "" {
+		_ = os.Setenv("COOKIES_PATH", cfg.Storage.CookiesPath)
+	}
+
+	// 兼容旧用法：位置参数指定默认账号使用的 cookies 文件后缀。
+	// 多账号请使用 /api/v1/accounts 接口在同一进程内管理。
 	args := flag.Args()
 	if len(args) > 0 {
 		suffix := strings.TrimSpace(args[0])
 		if suffix != "" {
-			base := cookies.GetCookiesFilePath()
-			routed := base + "." + suffix
+			routed := cookies.GetAccountCookiesFilePath(suffix)
 			_ = os.Setenv("COOKIES_PATH", routed)
 			logrus.Infof("使用路由 cookies 文件: %s", routed)
 		}
 	}
 
-	if len(binPath) == 0 {
-		binPath = os.Getenv("ROD_BROWSER_BIN")
+	// 未配置的存储路径使用各模块的默认路径，便于通过 /api/v1/config 查看实际生效的路径
+	storage := &cfg.Storage
+	storage.CookiesPath = cookies.GetCookiesFilePath()
+	storage.AccountsPath = pathOr(storage.AccountsPath, accounts.GetStorePath())
+	storage.ScheduleDBPath = pathOr(storage.ScheduleDBPath, schedule.GetStorePath())
+	storage.AuditLogPath = pathOr(storage.AuditLogPath, audit.GetLogPath())
+	storage.ArtifactsDir = pathOr(storage.ArtifactsDir, artifacts.GetDir())
+	storage.SelectorsPath = pathOr(storage.SelectorsPath, xiaohongshu.GetSelectorsPath())
+	configs.Set(cfg)
+
+	// 页面元素选择器，页面改版时可通过配置文件或环境变量覆盖
+	if err := xiaohongshu.LoadSelectors(storage.SelectorsPath, os.Getenv("SELECTORS_OVERRIDE")); err != nil {
+		logrus.Fatalf("failed to load selectors: %v", err)
+	}
+
+	// 初始化账号注册表，每个账号拥有独立的浏览器池，HTTP API 与 MCP 工具共享
+	registry, err := accounts.NewRegistry(storage.AccountsPath, browser.PoolConfig{
+		Size:        cfg.Browser.PoolSize,
+		IdleTimeout: cfg.Browser.PoolIdleTimeout.Duration,
+		Headless:    cfg.Browser.Headless,
+		BinPath:     cfg.Browser.BinPath,
+	})
+	if err != nil {
+		logrus.Fatalf("failed to load accounts: %v", err)
+	}
+	defer registry.Close()
+
+	// 打开定时发布队列，任务持久化在本地数据库中，重启后继续调度
+	store, err := schedule.OpenStore(storage.ScheduleDBPath)
+	if err != nil {
+		logrus.Fatalf("failed to open schedule store: %v", err)
+	}
+	defer store.Close()
+
+	// 写操作限流，限制规则来自配置的 rate_limits
+	var limiter *ratelimit.Limiter
+	if cfg.Features.RateLimit {
+		limiter = ratelimit.NewLimiter(rateLimitConfig(cfg.RateLimits))
+	}
+
+	// 打开写操作审计日志，只追加写入
+	var auditLog *audit.Log
+	if cfg.Features.AuditLog {
+		auditLog, err = audit.Open(storage.AuditLogPath)
+		if err != nil {
+			logrus.Fatalf("failed to open audit log: %v", err)
+		}
+		defer auditLog.Close()
 	}
 
-	configs.InitHeadless(headless)
-	configs.SetBinPath(binPath)
+	// 发布、详情等操作失败时保存页面快照的目录
+	var debugArtifacts *artifacts.Store
+	if cfg.Features.DebugArtifacts {
+		debugArtifacts, err = artifacts.Open(storage.ArtifactsDir, artifacts.DefaultMaxArtifacts)
+		if err != nil {
+			logrus.Fatalf("failed to open debug artifacts dir: %v", err)
+		}
+	}
 
 	// 初始化服务
-	xiaohongshuService := NewXiaohongshuService()
+	xiaohongshuService := NewXiaohongshuService(registry, store, limiter, auditLog, debugArtifacts)
 
 	// 创建并启动应用服务器
-	appServer := NewAppServer(xiaohongshuService)
-	if err := appServer.Start(port); err != nil {
+	appServer := NewAppServer(xiaohongshuService, registry)
+	xiaohongshuService.Start()
+	defer xiaohongshuService.Close()
+
+	if cfg.Server.Transport == configs.TransportStdio {
+		if err := appServer.ServeStdio(stdin, stdout); err != nil {
+			logrus.Fatalf("failed to run stdio server: %v", err)
+		}
+		return
+	}
+	if err := appServer.Start(cfg.Server.Addr); err != nil {
 		logrus.Fatalf("failed to run server: %v", err)
 	}
 }
+
+// pathOr 未配置路径时使用默认路径
+func pathOr(path, def string) string {
+	if path == "" {
+		return def
+	}
+	return path
+}
+
+// rateLimitConfig 将配置中的 rate_limits 转换为限流器的规则
+func rateLimitConfig(c configs.RateLimitsConfig) ratelimit.Config {
+	rule := func(r configs.RateLimitRule) ratelimit.Rule {
+		return ratelimit.Rule{PerMinute: r.PerMinute, Burst: r.Burst, Daily: r.Daily}
+	}
+	return ratelimit.Config{
+		ratelimit.ActionPublish:   rule(c.Publish),
+		ratelimit.ActionSaveDraft: rule(c.SaveDraft),
+		ratelimit.ActionComment:   rule(c.Comment),
+		ratelimit.ActionReply:     rule(c.Reply),
+		ratelimit.ActionLike:      rule(c.Like),
+		ratelimit.ActionFavorite:  rule(c.Favorite),
+		ratelimit.ActionFollow:    rule(c.Follow),
+	}
+}
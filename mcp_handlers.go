@@ -2,14 +2,21 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
-	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/artifacts"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/jobs"
+	"github.com/xpzouying/xiaohongshu-mcp/ratelimit"
+	"github.com/xpzouying/xiaohongshu-mcp/schedule"
 	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 )
 
@@ -21,29 +28,18 @@ func (s *AppServer) handleCheckLoginStatus(ctx context.Context) *MCPToolResult {
 
 	status, err := s.xiaohongshuService.CheckLoginStatus(ctx)
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "检查登录状态失败: " + err.Error(),
-			}},
-			IsError: true,
-		}
+		return errorResult("检查登录状态失败", err)
 	}
 
 	// 根据 IsLoggedIn 判断并返回友好的提示
 	var resultText string
 	if status.IsLoggedIn {
-		resultText = fmt.Sprintf("✅ 已登录\n用户名: %s\n\n你可以使用其他功能了。", status.Username)
+		resultText = fmt.Sprintf("✅ 已登录\n账号: %s\n用户名: %s\n\n你可以使用其他功能了。", status.Account, status.Username)
 	} else {
-		resultText = fmt.Sprintf("❌ 未登录\n\n请使用 get_login_qrcode 工具获取二维码进行登录。")
+		resultText = fmt.Sprintf("❌ 未登录\n账号: %s\n\n请使用 get_login_qrcode 工具获取二维码进行登录。", status.Account)
 	}
 
-	return &MCPToolResult{
-		Content: []MCPContent{{
-			Type: "text",
-			Text: resultText,
-		}},
-	}
+	return structuredResult(resultText, status)
 }
 
 // handleGetLoginQrcode 处理获取登录二维码请求。
@@ -53,16 +49,11 @@ func (s *AppServer) handleGetLoginQrcode(ctx context.Context) *MCPToolResult {
 
 	result, err := s.xiaohongshuService.GetLoginQrcode(ctx)
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{Type: "text", Text: "获取登录扫码图片失败: " + err.Error()}},
-			IsError: true,
-		}
+		return errorResult("获取登录扫码图片失败", err)
 	}
 
 	if result.IsLoggedIn {
-		return &MCPToolResult{
-			Content: []MCPContent{{Type: "text", Text: "你当前已处于登录状态"}},
-		}
+		return structuredResult("你当前已处于登录状态", result)
 	}
 
 	now := time.Now()
@@ -83,7 +74,11 @@ func (s *AppServer) handleGetLoginQrcode(ctx context.Context) *MCPToolResult {
 			Data:     strings.TrimPrefix(result.Img, "data:image/png;base64,"),
 		},
 	}
-	return &MCPToolResult{Content: contents}
+	// 二维码图片已在 image 内容中返回，结构化结果中不再重复
+	return &MCPToolResult{
+		Content:    contents,
+		Structured: &LoginQrcodeResponse{Timeout: result.Timeout, IsLoggedIn: result.IsLoggedIn},
+	}
 }
 
 // handleDeleteCookies 处理删除 cookies 请求，用于登录重置
@@ -92,20 +87,15 @@ func (s *AppServer) handleDeleteCookies(ctx context.Context) *MCPToolResult {
 
 	err := s.xiaohongshuService.DeleteCookies(ctx)
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{Type: "text", Text: "删除 cookies 失败: " + err.Error()}},
-			IsError: true,
-		}
+		return errorResult("删除 cookies 失败", err)
 	}
 
-	cookiePath := cookies.GetCookiesFilePath()
+	cookiePath := s.xiaohongshuService.CookiesFilePath(ctx)
 	resultText := fmt.Sprintf("Cookies 已成功删除，登录状态已重置。\n\n删除的文件路径: %s\n\n下次操作时，需要重新登录。", cookiePath)
-	return &MCPToolResult{
-		Content: []MCPContent{{
-			Type: "text",
-			Text: resultText,
-		}},
-	}
+	return structuredResult(resultText, &DeleteCookiesResponse{
+		Account:     accounts.FromContext(ctx),
+		CookiesPath: cookiePath,
+	})
 }
 
 // handlePublishContent 处理发布内容
@@ -142,25 +132,50 @@ func (s *AppServer) handlePublishContent(ctx context.Context, args map[string]in
 		Tags:    tags,
 	}
 
-	// 执行发布
-	result, err := s.xiaohongshuService.PublishContent(ctx, req)
-	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "发布失败: " + err.Error(),
-			}},
-			IsError: true,
+	// 仅保存为草稿
+	if saveAsDraft, _ := args["save_as_draft"].(bool); saveAsDraft {
+		if publishAt, _ := args["publish_at"].(string); publishAt != "" {
+			return errorResult("保存草稿失败", errors.New("save_as_draft 和 publish_at 不能同时指定"))
 		}
+
+		job, err := s.xiaohongshuService.SaveDraftAsync(ctx, req)
+		if err != nil {
+			return errorResult("保存草稿失败", err)
+		}
+
+		return structuredResult(
+			fmt.Sprintf("保存草稿任务已提交，任务ID: %s\n\n请使用 get_job 工具查询进度，完成后可在创作中心草稿箱中审核发布。", job.ID),
+			&PublishSubmitResponse{Job: job},
+		)
 	}
 
-	resultText := fmt.Sprintf("内容发布成功: %+v", result)
-	return &MCPToolResult{
-		Content: []MCPContent{{
-			Type: "text",
-			Text: resultText,
-		}},
+	// 指定了发布时间时加入定时队列
+	if publishAt, _ := args["publish_at"].(string); publishAt != "" {
+		at, err := time.Parse(time.RFC3339, publishAt)
+		if err != nil {
+			return errorResult("发布失败: publish_at 格式错误，请使用 RFC3339 格式", err)
+		}
+		req.PublishAt = &at
+
+		task, err := s.xiaohongshuService.SchedulePublish(ctx, req)
+		if err != nil {
+			return errorResult("定时发布失败", err)
+		}
+
+		return structuredResult(
+			fmt.Sprintf("已加入定时发布队列，任务ID: %s，发布时间: %s", task.ID, task.PublishAt.Format(time.RFC3339)),
+			&PublishSubmitResponse{Task: task},
+		)
 	}
+
+	// 提交发布任务，发布在后台执行
+	job, err := s.xiaohongshuService.PublishContentAsync(ctx, req)
+	if err != nil {
+		return errorResult("发布失败", err)
+	}
+
+	resultText := fmt.Sprintf("发布任务已提交，任务ID: %s\n\n发布在后台进行，请使用 get_job 工具查询进度。", job.ID)
+	return structuredResult(resultText, &PublishSubmitResponse{Job: job})
 }
 
 // handlePublishVideo 处理发布视频内容（仅本地单个视频文件）
@@ -199,60 +214,66 @@ func (s *AppServer) handlePublishVideo(ctx context.Context, args map[string]inte
 		Tags:    tags,
 	}
 
-	// 执行发布
-	result, err := s.xiaohongshuService.PublishVideo(ctx, req)
-	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "发布失败: " + err.Error(),
-			}},
-			IsError: true,
+	// 仅保存为草稿
+	if saveAsDraft, _ := args["save_as_draft"].(bool); saveAsDraft {
+		if publishAt, _ := args["publish_at"].(string); publishAt != "" {
+			return errorResult("保存草稿失败", errors.New("save_as_draft 和 publish_at 不能同时指定"))
 		}
-	}
 
-	resultText := fmt.Sprintf("视频发布成功: %+v", result)
-	return &MCPToolResult{
-		Content: []MCPContent{{
-			Type: "text",
-			Text: resultText,
-		}},
+		job, err := s.xiaohongshuService.SaveVideoDraftAsync(ctx, req)
+		if err != nil {
+			return errorResult("保存草稿失败", err)
+		}
+
+		return structuredResult(
+			fmt.Sprintf("保存草稿任务已提交，任务ID: %s\n\n请使用 get_job 工具查询进度，完成后可在创作中心草稿箱中审核发布。", job.ID),
+			&PublishSubmitResponse{Job: job},
+		)
 	}
-}
 
-// handleListFeeds 处理获取Feeds列表
-func (s *AppServer) handleListFeeds(ctx context.Context) *MCPToolResult {
-	logrus.Info("MCP: 获取Feeds列表")
+	// 指定了发布时间时加入定时队列
+	if publishAt, _ := args["publish_at"].(string); publishAt != "" {
+		at, err := time.Parse(time.RFC3339, publishAt)
+		if err != nil {
+			return errorResult("发布失败: publish_at 格式错误，请使用 RFC3339 格式", err)
+		}
+		req.PublishAt = &at
 
-	result, err := s.xiaohongshuService.ListFeeds(ctx)
-	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "获取Feeds列表失败: " + err.Error(),
-			}},
-			IsError: true,
+		task, err := s.xiaohongshuService.SchedulePublishVideo(ctx, req)
+		if err != nil {
+			return errorResult("定时发布失败", err)
 		}
+
+		return structuredResult(
+			fmt.Sprintf("已加入定时发布队列，任务ID: %s，发布时间: %s", task.ID, task.PublishAt.Format(time.RFC3339)),
+			&PublishSubmitResponse{Task: task},
+		)
 	}
 
-	// 格式化输出，转换为JSON字符串
-	jsonData, err := json.MarshalIndent(result, "", "  ")
+	// 提交发布任务，视频上传与处理在后台执行
+	job, err := s.xiaohongshuService.PublishVideoAsync(ctx, req)
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: fmt.Sprintf("获取Feeds列表成功，但序列化失败: %v", err),
-			}},
-			IsError: true,
-		}
+		return errorResult("发布失败", err)
 	}
 
-	return &MCPToolResult{
-		Content: []MCPContent{{
-			Type: "text",
-			Text: string(jsonData),
-		}},
+	resultText := fmt.Sprintf("视频发布任务已提交，任务ID: %s\n\n视频上传耗时较长，请使用 get_job 工具查询进度。", job.ID)
+	return structuredResult(resultText, &PublishSubmitResponse{Job: job})
+}
+
+// handleListFeeds 处理获取Feeds列表
+func (s *AppServer) handleListFeeds(ctx context.Context, args ListFeedsArgs) *MCPToolResult {
+	logrus.Infof("MCP: 获取Feeds列表 - channel: %s, limit: %d", args.Channel, args.Limit)
+
+	result, err := s.xiaohongshuService.ListFeeds(ctx, ListFeedsOptions{
+		Channel: args.Channel,
+		Limit:   args.Limit,
+		Cursor:  args.Cursor,
+	})
+	if err != nil {
+		return errorResult("获取Feeds列表失败", err)
 	}
+
+	return structuredResult(summarizeFeeds("获取Feeds列表成功", result), result)
 }
 
 // handleSearchFeeds 处理搜索Feeds
@@ -269,7 +290,7 @@ func (s *AppServer) handleSearchFeeds(ctx context.Context, args SearchFeedsArgs)
 		}
 	}
 
-	logrus.Infof("MCP: 搜索Feeds - 关键词: %s", args.Keyword)
+	logrus.Infof("MCP: 搜索Feeds - 关键词: %s, limit: %d, page: %d", args.Keyword, args.Limit, args.Page)
 
 	// 将 MCP 的 FilterOption 转换为 xiaohongshu.FilterOption
 	filter := xiaohongshu.FilterOption{
@@ -280,35 +301,13 @@ func (s *AppServer) handleSearchFeeds(ctx context.Context, args SearchFeedsArgs)
 		Location:    args.Filters.Location,
 	}
 
-	result, err := s.xiaohongshuService.SearchFeeds(ctx, args.Keyword, filter)
+	opts := xiaohongshu.PageOptions{Limit: args.Limit, Page: args.Page}
+	result, err := s.xiaohongshuService.SearchFeeds(ctx, args.Keyword, opts, filter)
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "搜索Feeds失败: " + err.Error(),
-			}},
-			IsError: true,
-		}
-	}
-
-	// 格式化输出，转换为JSON字符串
-	jsonData, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: fmt.Sprintf("搜索Feeds成功，但序列化失败: %v", err),
-			}},
-			IsError: true,
-		}
+		return errorResult("搜索Feeds失败", err)
 	}
 
-	return &MCPToolResult{
-		Content: []MCPContent{{
-			Type: "text",
-			Text: string(jsonData),
-		}},
-	}
+	return structuredResult(summarizeFeeds("搜索Feeds成功", result), result)
 }
 
 // handleGetFeedDetail 处理获取Feed详情
@@ -400,33 +399,10 @@ func (s *AppServer) handleGetFeedDetail(ctx context.Context, args map[string]any
 
 	result, err := s.xiaohongshuService.GetFeedDetailWithConfig(ctx, feedID, xsecToken, loadAll, config)
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "获取Feed详情失败: " + err.Error(),
-			}},
-			IsError: true,
-		}
+		return errorResult("获取Feed详情失败", err)
 	}
 
-	// 格式化输出，转换为JSON字符串
-	jsonData, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: fmt.Sprintf("获取Feed详情成功，但序列化失败: %v", err),
-			}},
-			IsError: true,
-		}
-	}
-
-	return &MCPToolResult{
-		Content: []MCPContent{{
-			Type: "text",
-			Text: string(jsonData),
-		}},
-	}
+	return structuredResult(summarizeFeedDetail(result), result)
 }
 
 // handleUserProfile 获取用户主页
@@ -456,37 +432,24 @@ func (s *AppServer) handleUserProfile(ctx context.Context, args map[string]any)
 		}
 	}
 
-	logrus.Infof("MCP: 获取用户主页 - User ID: %s", userID)
-
-	result, err := s.xiaohongshuService.UserProfile(ctx, userID, xsecToken)
+	tabName, _ := args["tab"].(string)
+	tab, err := xiaohongshu.ParseProfileTab(tabName)
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "获取用户主页失败: " + err.Error(),
-			}},
-			IsError: true,
-		}
+		return errorResult("获取用户主页失败", err)
 	}
 
-	// 格式化输出，转换为JSON字符串
-	jsonData, err := json.MarshalIndent(result, "", "  ")
+	var opts xiaohongshu.PageOptions
+	opts.Limit, _ = args["limit"].(int)
+	opts.Page, _ = args["page"].(int)
+
+	logrus.Infof("MCP: 获取用户主页 - User ID: %s, tab: %s, limit: %d, page: %d", userID, tab, opts.Limit, opts.Page)
+
+	result, err := s.xiaohongshuService.UserProfile(ctx, userID, xsecToken, tab, opts)
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: fmt.Sprintf("获取用户主页，但序列化失败: %v", err),
-			}},
-			IsError: true,
-		}
+		return errorResult("获取用户主页失败", err)
 	}
 
-	return &MCPToolResult{
-		Content: []MCPContent{{
-			Type: "text",
-			Text: string(jsonData),
-		}},
-	}
+	return structuredResult(summarizeUserProfile(result), result)
 }
 
 // handleLikeFeed 处理点赞/取消点赞
@@ -515,14 +478,77 @@ func (s *AppServer) handleLikeFeed(ctx context.Context, args map[string]interfac
 		if unlike {
 			action = "取消点赞"
 		}
-		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: action + "失败: " + err.Error()}}, IsError: true}
+		return errorResult(action+"失败", err)
 	}
 
 	action := "点赞"
 	if unlike {
 		action = "取消点赞"
 	}
-	return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("%s成功 - Feed ID: %s", action, res.FeedID)}}}
+	return structuredResult(fmt.Sprintf("%s成功 - Feed ID: %s", action, res.FeedID), res)
+}
+
+// handleFollowUser 处理关注/取消关注
+func (s *AppServer) handleFollowUser(ctx context.Context, args FollowUserArgs, follow bool) *MCPToolResult {
+	action := "关注"
+	if !follow {
+		action = "取消关注"
+	}
+
+	if args.UserID == "" {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: action + "失败: 缺少user_id参数"}}, IsError: true}
+	}
+	if args.XsecToken == "" {
+		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: action + "失败: 缺少xsec_token参数"}}, IsError: true}
+	}
+
+	logrus.Infof("MCP: %s - User ID: %s", action, args.UserID)
+
+	var res *xiaohongshu.FollowResult
+	var err error
+	if follow {
+		res, err = s.xiaohongshuService.FollowUser(ctx, args.UserID, args.XsecToken)
+	} else {
+		res, err = s.xiaohongshuService.UnfollowUser(ctx, args.UserID, args.XsecToken)
+	}
+	if err != nil {
+		return errorResult(action+"失败", err)
+	}
+
+	status := "未改变，原本已是目标状态"
+	if res.Changed {
+		status = "已改变"
+	}
+	return structuredResult(
+		fmt.Sprintf("%s成功 - User ID: %s, followed: %t, changed: %t（关注状态%s）", action, res.UserID, res.Followed, res.Changed, status),
+		res,
+	)
+}
+
+// handleBatchInteract 处理批量互动
+func (s *AppServer) handleBatchInteract(ctx context.Context, args BatchInteractArgs) *MCPToolResult {
+	logrus.Infof("MCP: 批量互动 - %d 项操作", len(args.Operations))
+
+	ops := make([]BatchOperation, 0, len(args.Operations))
+	for _, op := range args.Operations {
+		ops = append(ops, BatchOperation{
+			Action:    op.Action,
+			FeedID:    op.FeedID,
+			UserID:    op.UserID,
+			XsecToken: op.XsecToken,
+			Content:   op.Content,
+		})
+	}
+
+	result, err := s.xiaohongshuService.BatchInteract(ctx, ops)
+	if err != nil {
+		return errorResult("批量操作失败", err)
+	}
+
+	return structuredResult(
+		fmt.Sprintf("批量操作完成: 成功 %d 项，失败 %d 项，跳过 %d 项", result.Succeeded, result.Failed, result.Skipped),
+		result,
+	)
 }
 
 // handleFavoriteFeed 处理收藏/取消收藏
@@ -551,14 +577,14 @@ func (s *AppServer) handleFavoriteFeed(ctx context.Context, args map[string]inte
 		if unfavorite {
 			action = "取消收藏"
 		}
-		return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: action + "失败: " + err.Error()}}, IsError: true}
+		return errorResult(action+"失败", err)
 	}
 
 	action := "收藏"
 	if unfavorite {
 		action = "取消收藏"
 	}
-	return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: fmt.Sprintf("%s成功 - Feed ID: %s", action, res.FeedID)}}}
+	return structuredResult(fmt.Sprintf("%s成功 - Feed ID: %s", action, res.FeedID), res)
 }
 
 // handlePostComment 处理发表评论到Feed
@@ -604,23 +630,11 @@ func (s *AppServer) handlePostComment(ctx context.Context, args map[string]inter
 	// 发表评论
 	result, err := s.xiaohongshuService.PostCommentToFeed(ctx, feedID, xsecToken, content)
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "发表评论失败: " + err.Error(),
-			}},
-			IsError: true,
-		}
+		return errorResult("发表评论失败", err)
 	}
 
-	// 返回成功结果，只包含feed_id
 	resultText := fmt.Sprintf("评论发表成功 - Feed ID: %s", result.FeedID)
-	return &MCPToolResult{
-		Content: []MCPContent{{
-			Type: "text",
-			Text: resultText,
-		}},
-	}
+	return structuredResult(resultText, result)
 }
 
 // handleReplyComment 处理回复评论
@@ -678,21 +692,362 @@ func (s *AppServer) handleReplyComment(ctx context.Context, args map[string]inte
 	// 回复评论
 	result, err := s.xiaohongshuService.ReplyCommentToFeed(ctx, feedID, xsecToken, commentID, userID, content)
 	if err != nil {
+		return errorResult("回复评论失败", err)
+	}
+
+	// 返回成功结果
+	responseText := fmt.Sprintf("评论回复成功 - Feed ID: %s, Comment ID: %s, User ID: %s", result.FeedID, result.TargetCommentID, result.TargetUserID)
+	return structuredResult(responseText, result)
+}
+
+// handleListAccounts 处理列出账号
+func (s *AppServer) handleListAccounts(ctx context.Context) *MCPToolResult {
+	logrus.Info("MCP: 列出账号")
+
+	list := s.accounts.List()
+
+	names := make([]string, 0, len(list))
+	for _, account := range list {
+		if account.NeedsHuman {
+			names = append(names, account.Name+"（遇到验证码，已暂停）")
+			continue
+		}
+		names = append(names, account.Name)
+	}
+
+	return structuredResult(
+		fmt.Sprintf("共 %d 个账号: %s", len(list), strings.Join(names, "、")),
+		&AccountsListResponse{Accounts: list, Count: len(list)},
+	)
+}
+
+// handleGetAccountChallenge 处理查看账号验证码
+func (s *AppServer) handleGetAccountChallenge(ctx context.Context) *MCPToolResult {
+	logrus.Info("MCP: 查看账号验证码")
+
+	challenge, err := s.xiaohongshuService.GetChallenge(ctx)
+	if err != nil {
+		return errorResult("账号没有待处理的验证码", err)
+	}
+
+	contents := []MCPContent{{
+		Type: "text",
+		Text: fmt.Sprintf("账号 %s 于 %s 遇到验证码，已暂停使用\n原因: %s\n页面: %s\n\n请在有界面的浏览器中完成验证后调用 resume_account 恢复账号。",
+			challenge.Account, challenge.DetectedAt.Format("2006-01-02 15:04:05"), challenge.Reason, challenge.URL),
+	}}
+	if len(challenge.Screenshot) > 0 {
+		contents = append(contents, MCPContent{
+			Type:     "image",
+			MimeType: "image/png",
+			Data:     base64.StdEncoding.EncodeToString(challenge.Screenshot),
+		})
+	}
+
+	// 截图已在 image 内容中返回，结构化结果中不再重复
+	structured := *challenge
+	structured.Screenshot = nil
+	return &MCPToolResult{Content: contents, Structured: &structured}
+}
+
+// handleResumeAccount 处理恢复账号
+func (s *AppServer) handleResumeAccount(ctx context.Context) *MCPToolResult {
+	logrus.Info("MCP: 恢复账号")
+
+	if err := s.xiaohongshuService.ResumeAccount(ctx); err != nil {
+		return errorResult("恢复账号失败", err)
+	}
+
+	account := accounts.FromContext(ctx)
+	return structuredResult(fmt.Sprintf("账号 %s 已恢复", account), &ResumeAccountResponse{Account: account})
+}
+
+// handleGetJob 处理查询异步任务
+func (s *AppServer) handleGetJob(ctx context.Context, args JobArgs) *MCPToolResult {
+	logrus.Infof("MCP: 查询任务 - Job ID: %s", args.JobID)
+
+	if args.JobID == "" {
 		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "回复评论失败: " + err.Error(),
-			}},
+			Content: []MCPContent{{Type: "text", Text: "查询任务失败: 缺少job_id参数"}},
 			IsError: true,
 		}
 	}
 
-	// 返回成功结果
-	responseText := fmt.Sprintf("评论回复成功 - Feed ID: %s, Comment ID: %s, User ID: %s", result.FeedID, result.TargetCommentID, result.TargetUserID)
+	job, err := s.xiaohongshuService.GetJob(args.JobID)
+	if err != nil {
+		return errorResult("查询任务失败", err)
+	}
+
+	return structuredResult(summarizeJob(job), job)
+}
+
+// handleCancelJob 处理取消异步任务
+func (s *AppServer) handleCancelJob(ctx context.Context, args JobArgs) *MCPToolResult {
+	logrus.Infof("MCP: 取消任务 - Job ID: %s", args.JobID)
+
+	if args.JobID == "" {
+		return &MCPToolResult{
+			Content: []MCPContent{{Type: "text", Text: "取消任务失败: 缺少job_id参数"}},
+			IsError: true,
+		}
+	}
+
+	job, err := s.xiaohongshuService.CancelJob(args.JobID)
+	if err != nil {
+		return errorResult("取消任务失败", err)
+	}
+
+	return structuredResult(fmt.Sprintf("任务已取消 - Job ID: %s", job.ID), job)
+}
+
+// handleListSchedules 处理查询定时发布任务
+func (s *AppServer) handleListSchedules(ctx context.Context, args ListSchedulesArgs) *MCPToolResult {
+	logrus.Infof("MCP: 查询定时发布任务 - status: %s", args.Status)
+
+	tasks, err := s.xiaohongshuService.ListScheduled(schedule.Status(args.Status))
+	if err != nil {
+		return errorResult("查询定时任务失败", err)
+	}
+
+	return structuredResult(summarizeSchedules(tasks), &SchedulesListResponse{Tasks: tasks, Count: len(tasks)})
+}
+
+// handleReschedule 处理修改定时发布时间
+func (s *AppServer) handleReschedule(ctx context.Context, args RescheduleArgs) *MCPToolResult {
+	logrus.Infof("MCP: 修改定时发布时间 - Task ID: %s, publish_at: %s", args.TaskID, args.PublishAt)
+
+	publishAt, err := time.Parse(time.RFC3339, args.PublishAt)
+	if err != nil {
+		return errorResult("改期失败: publish_at 格式错误，请使用 RFC3339 格式", err)
+	}
+
+	task, err := s.xiaohongshuService.RescheduleTask(args.TaskID, publishAt)
+	if err != nil {
+		return errorResult("改期失败", err)
+	}
+
+	return structuredResult(
+		fmt.Sprintf("定时任务已改期 - Task ID: %s，新的发布时间: %s", task.ID, task.PublishAt.Format(time.RFC3339)),
+		task,
+	)
+}
+
+// handleCancelSchedule 处理取消定时发布
+func (s *AppServer) handleCancelSchedule(ctx context.Context, args ScheduleTaskArgs) *MCPToolResult {
+	logrus.Infof("MCP: 取消定时发布 - Task ID: %s", args.TaskID)
+
+	task, err := s.xiaohongshuService.CancelScheduled(args.TaskID)
+	if err != nil {
+		return errorResult("取消定时任务失败", err)
+	}
+
+	return structuredResult(fmt.Sprintf("定时任务已取消 - Task ID: %s", task.ID), task)
+}
+
+// handleListDrafts 处理查询草稿箱
+func (s *AppServer) handleListDrafts(ctx context.Context, args ListDraftsArgs) *MCPToolResult {
+	logrus.Infof("MCP: 查询草稿箱 - type: %s", args.Type)
+
+	typ, err := xiaohongshu.ParseDraftType(args.Type)
+	if err != nil {
+		return errorResult("查询草稿失败", err)
+	}
+
+	result, err := s.xiaohongshuService.ListDrafts(ctx, typ)
+	if err != nil {
+		return errorResult("查询草稿失败", err)
+	}
+
+	return structuredResult(summarizeDrafts(result), result)
+}
+
+// handleOpenDraft 处理打开草稿
+func (s *AppServer) handleOpenDraft(ctx context.Context, args DraftArgs) *MCPToolResult {
+	logrus.Infof("MCP: 打开草稿 - type: %s, index: %d", args.Type, args.Index)
+
+	ref, err := draftRefFromArgs(args)
+	if err != nil {
+		return errorResult("打开草稿失败", err)
+	}
+
+	result, err := s.xiaohongshuService.OpenDraft(ctx, ref)
+	if err != nil {
+		return errorResult("打开草稿失败", err)
+	}
+
+	return structuredResult(fmt.Sprintf("草稿「%s」\n\n%s", result.Title, result.Content), result)
+}
+
+// handlePublishDraft 处理发布草稿
+func (s *AppServer) handlePublishDraft(ctx context.Context, args DraftArgs) *MCPToolResult {
+	logrus.Infof("MCP: 发布草稿 - type: %s, index: %d, title: %s", args.Type, args.Index, args.Title)
+
+	ref, err := draftRefFromArgs(args)
+	if err != nil {
+		return errorResult("发布草稿失败", err)
+	}
+
+	job, err := s.xiaohongshuService.PublishDraftAsync(ctx, ref)
+	if err != nil {
+		return errorResult("发布草稿失败", err)
+	}
+
+	return structuredResult(fmt.Sprintf("发布草稿任务已提交，任务ID: %s\n\n请使用 get_job 工具查询进度。", job.ID), job)
+}
+
+func draftRefFromArgs(args DraftArgs) (xiaohongshu.DraftRef, error) {
+	typ, err := xiaohongshu.ParseDraftType(args.Type)
+	if err != nil {
+		return xiaohongshu.DraftRef{}, err
+	}
+	if args.Index < 0 {
+		return xiaohongshu.DraftRef{}, fmt.Errorf("草稿位置必须是非负整数")
+	}
+
+	return xiaohongshu.DraftRef{Type: typ, Index: args.Index, Title: args.Title}, nil
+}
+
+// structuredResult 返回成功的结果：text 为简短的文字摘要，data 作为 structuredContent 返回，
+// 类型需与工具注册时声明的 OutputSchema 一致。
+func structuredResult(text string, data any) *MCPToolResult {
+	return &MCPToolResult{
+		Content:    []MCPContent{{Type: "text", Text: text}},
+		Structured: data,
+	}
+}
+
+// summarizeFeeds 生成笔记列表的文字摘要，每条笔记一行
+func summarizeFeeds(title string, result *FeedsListResponse) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s，共 %d 条笔记", title, result.Count)
+	if result.HasMore {
+		b.WriteString("，还有更多")
+	}
+	if result.NextCursor != "" {
+		fmt.Fprintf(&b, "，next_cursor: %s", result.NextCursor)
+	}
+	for i, feed := range result.Feeds {
+		fmt.Fprintf(&b, "\n%d. %s - %s（点赞 %s）", i+1, feed.NoteCard.DisplayTitle, nickname(feed.NoteCard.User), feed.NoteCard.InteractInfo.LikedCount)
+	}
+	return b.String()
+}
+
+// summarizeFeedDetail 生成笔记详情的文字摘要
+func summarizeFeedDetail(result *FeedDetailResponse) string {
+	if result.Data == nil {
+		return fmt.Sprintf("获取Feed详情成功 - Feed ID: %s", result.FeedID)
+	}
+
+	note := result.Data.Note
+	comments := result.Data.Comments
+	var b strings.Builder
+	fmt.Fprintf(&b, "笔记「%s」- %s\n", note.Title, nickname(note.User))
+	fmt.Fprintf(&b, "点赞 %s，收藏 %s，评论 %s，分享 %s\n",
+		note.InteractInfo.LikedCount, note.InteractInfo.CollectedCount, note.InteractInfo.CommentCount, note.InteractInfo.SharedCount)
+	fmt.Fprintf(&b, "已加载 %d 条一级评论", len(comments.List))
+	if comments.HasMore {
+		b.WriteString("，还有更多")
+	}
+	return b.String()
+}
+
+// summarizeUserProfile 生成用户主页的文字摘要
+func summarizeUserProfile(result *UserProfileResponse) string {
+	info := result.UserBasicInfo
+	var b strings.Builder
+	fmt.Fprintf(&b, "用户 %s（小红书号 %s，IP属地 %s）", info.Nickname, info.RedId, info.IpLocation)
+	for i, interaction := range result.Interactions {
+		if i == 0 {
+			b.WriteString("\n")
+		} else {
+			b.WriteString("，")
+		}
+		fmt.Fprintf(&b, "%s %s", interaction.Name, interaction.Count)
+	}
+	fmt.Fprintf(&b, "\n返回 %d 篇笔记", len(result.Feeds))
+	if result.HasMore {
+		b.WriteString("，还有更多")
+	}
+	return b.String()
+}
+
+// summarizeJob 生成异步任务状态的文字摘要
+func summarizeJob(job *jobs.Job) string {
+	text := fmt.Sprintf("任务 %s（%s）当前阶段: %s", job.ID, job.Type, job.Stage)
+	if job.Progress != nil && job.Progress.Message != "" && !job.Finished() {
+		text += "，" + job.Progress.Message
+	}
+	if job.Error != "" {
+		text += "\n错误: " + job.Error
+	}
+	return text
+}
+
+// summarizeSchedules 生成定时发布任务列表的文字摘要，每个任务一行
+func summarizeSchedules(tasks []schedule.Task) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "共 %d 个定时发布任务", len(tasks))
+	for _, task := range tasks {
+		fmt.Fprintf(&b, "\n- %s（%s）%s，发布时间: %s", task.ID, task.Type, task.Status, task.PublishAt.Format(time.RFC3339))
+	}
+	return b.String()
+}
+
+// summarizeDrafts 生成草稿列表的文字摘要，每篇草稿一行
+func summarizeDrafts(result *DraftsListResponse) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "草稿箱共 %d 篇草稿", result.Count)
+	for _, draft := range result.Drafts {
+		fmt.Fprintf(&b, "\n%d. %s", draft.Index, draft.Title)
+		if draft.UpdatedAt != "" {
+			fmt.Fprintf(&b, "（%s）", draft.UpdatedAt)
+		}
+	}
+	return b.String()
+}
+
+// nickname 返回用户昵称，页面数据中昵称字段可能是 nickname 或 nickName
+func nickname(user xiaohongshu.User) string {
+	if user.Nickname != "" {
+		return user.Nickname
+	}
+	return user.NickName
+}
+
+// errorResult 返回工具执行失败的结果。
+// 限流和带错误代码的错误在文本中注明错误代码，并通过 Code 返回给客户端。
+func errorResult(message string, err error) *MCPToolResult {
+	if result := rateLimitedResult(err); result != nil {
+		return result
+	}
+
+	code := string(myerrors.CodeOf(err))
+	text := message + ": " + err.Error()
+	if code != "" {
+		text = fmt.Sprintf("%s（%s）: %s", message, code, err.Error())
+	}
+
+	return &MCPToolResult{
+		Content:    []MCPContent{{Type: "text", Text: text}},
+		IsError:    true,
+		Code:       code,
+		ArtifactID: artifacts.IDOf(err),
+	}
+}
+
+// rateLimitedResult 写操作被限流时返回包含重试时间的错误结果，其他错误返回 nil
+func rateLimitedResult(err error) *MCPToolResult {
+	var limitErr *ratelimit.LimitError
+	if !errors.As(err, &limitErr) {
+		return nil
+	}
+
+	details, _ := json.MarshalIndent(newRateLimitDetails(limitErr), "", "  ")
 	return &MCPToolResult{
 		Content: []MCPContent{{
 			Type: "text",
-			Text: responseText,
+			Text: fmt.Sprintf("操作被限流（%s）: %s\n\n%s", myerrors.CodeRateLimited, limitErr.Error(), string(details)),
 		}},
+		IsError: true,
+		Code:    string(myerrors.CodeRateLimited),
 	}
 }
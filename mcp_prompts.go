@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// 提示词中最多引用的参考笔记数量
+const promptReferenceFeeds = 10
+
+// accountPromptArgument 各提示词共用的账号参数
+var accountPromptArgument = &mcp.PromptArgument{
+	Name:        "account",
+	Description: "账号名称（可选），不填使用默认账号",
+}
+
+// registerPrompts 注册 MCP 提示词
+func registerPrompts(server *mcp.Server, appServer *AppServer) {
+	prompts := []struct {
+		prompt  *mcp.Prompt
+		handler mcp.PromptHandler
+	}{
+		{
+			&mcp.Prompt{
+				Name:        "draft_note",
+				Title:       "根据主题撰写笔记",
+				Description: "搜索主题下的热门笔记作为参考，生成标题、正文和话题标签，可直接用于 publish_content",
+				Arguments: []*mcp.PromptArgument{
+					{Name: "topic", Description: "笔记主题，如 周末露营装备", Required: true},
+					{Name: "style", Description: "写作风格（可选），如 种草、测评、教程、日常分享"},
+					accountPromptArgument,
+				},
+			},
+			withPromptRecovery("draft_note", appServer.draftNotePrompt),
+		},
+		{
+			&mcp.Prompt{
+				Name:        "summarize_comments",
+				Title:       "总结笔记评论",
+				Description: "读取笔记内容和评论，总结主要观点、情绪倾向、常见问题和值得回复的评论",
+				Arguments: []*mcp.PromptArgument{
+					{Name: "feed_id", Description: "小红书笔记ID，从Feed列表获取", Required: true},
+					{Name: "xsec_token", Description: "访问令牌，从Feed列表的xsecToken字段获取", Required: true},
+					{Name: "load_all_comments", Description: "是否滚动加载更多评论（可选），true 或 false，默认 false 仅读取前10条一级评论"},
+					accountPromptArgument,
+				},
+			},
+			withPromptRecovery("summarize_comments", appServer.summarizeCommentsPrompt),
+		},
+		{
+			&mcp.Prompt{
+				Name:        "analyze_creator",
+				Title:       "分析博主",
+				Description: "读取用户主页的基本信息、粉丝数据和笔记，分析内容定位、爆款特征和合作价值",
+				Arguments: []*mcp.PromptArgument{
+					{Name: "user_id", Description: "小红书用户ID，从Feed列表获取", Required: true},
+					{Name: "xsec_token", Description: "访问令牌，从Feed列表的xsecToken字段获取", Required: true},
+					accountPromptArgument,
+				},
+			},
+			withPromptRecovery("analyze_creator", appServer.analyzeCreatorPrompt),
+		},
+	}
+
+	for _, p := range prompts {
+		server.AddPrompt(p.prompt, p.handler)
+	}
+	logrus.Infof("Registered %d MCP prompts", len(prompts))
+}
+
+// withPromptRecovery 捕获提示词生成时的 panic，返回错误而不是中断连接
+func withPromptRecovery(name string, handler func(context.Context, map[string]string) (*mcp.GetPromptResult, error)) mcp.PromptHandler {
+	return func(ctx context.Context, req *mcp.GetPromptRequest) (result *mcp.GetPromptResult, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logrus.WithFields(logrus.Fields{
+					"prompt": name,
+					"panic":  r,
+				}).Error("Prompt handler panicked")
+				logrus.Errorf("Stack trace:\n%s", debug.Stack())
+
+				result = nil
+				err = fmt.Errorf("生成提示词 %s 时发生内部错误: %v", name, r)
+			}
+		}()
+
+		args := req.Params.Arguments
+		if args == nil {
+			args = map[string]string{}
+		}
+		ctx = accounts.WithAccount(ctx, args["account"])
+		return handler(ctx, args)
+	}
+}
+
+// userPrompt 返回只包含一条用户消息的提示词
+func userPrompt(description, text string) *mcp.GetPromptResult {
+	return &mcp.GetPromptResult{
+		Description: description,
+		Messages: []*mcp.PromptMessage{{
+			Role:    "user",
+			Content: &mcp.TextContent{Text: text},
+		}},
+	}
+}
+
+func (s *AppServer) draftNotePrompt(ctx context.Context, args map[string]string) (*mcp.GetPromptResult, error) {
+	topic := strings.TrimSpace(args["topic"])
+	if topic == "" {
+		return nil, fmt.Errorf("生成提示词失败: 缺少 topic 参数")
+	}
+	logrus.Infof("MCP: 生成撰写笔记提示词 - 主题: %s", topic)
+
+	feeds, err := s.xiaohongshuService.SearchFeeds(ctx, topic, xiaohongshu.PageOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("搜索参考笔记失败: %w", err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "请以「%s」为主题撰写一篇小红书图文笔记。", topic)
+	if style := strings.TrimSpace(args["style"]); style != "" {
+		fmt.Fprintf(&b, "写作风格: %s。", style)
+	}
+	b.WriteString("\n\n")
+
+	if len(feeds.Feeds) > 0 {
+		b.WriteString("以下是该主题下的热门笔记，供参考选题角度和标题写法，不要照抄：\n")
+		for i, feed := range feeds.Feeds {
+			if i >= promptReferenceFeeds {
+				break
+			}
+			card := feed.NoteCard
+			fmt.Fprintf(&b, "%d. %s - %s（点赞 %s，收藏 %s，评论 %s）\n", i+1, card.DisplayTitle, nickname(card.User),
+				card.InteractInfo.LikedCount, card.InteractInfo.CollectedCount, card.InteractInfo.CommentCount)
+		}
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, `要求：
+- 标题：不超过 %d 个长度单位（中文、日文、韩文每个字计 2，英文字母、数字和符号每个计 1，约 %d 个汉字），吸引人但不要标题党
+- 正文：300~800 字，分段清晰，可以适当使用 emoji；不要在正文中写 # 开头的话题标签
+- 标签：3~10 个与主题相关的话题标签，不带 # 号
+
+请按以下格式输出：
+标题：……
+正文：……
+标签：标签1, 标签2, ……
+
+确认内容后可调用 publish_content 工具发布：title、content、tags 分别对应以上三项，images 需要至少一张图片；设置 save_as_draft=true 可先保存为草稿人工审核。`,
+		maxTitleWidth, maxTitleWidth/2)
+
+	return userPrompt(fmt.Sprintf("撰写主题为「%s」的小红书笔记", topic), b.String()), nil
+}
+
+func (s *AppServer) summarizeCommentsPrompt(ctx context.Context, args map[string]string) (*mcp.GetPromptResult, error) {
+	feedID := strings.TrimSpace(args["feed_id"])
+	xsecToken := strings.TrimSpace(args["xsec_token"])
+	if feedID == "" || xsecToken == "" {
+		return nil, fmt.Errorf("生成提示词失败: 缺少 feed_id 或 xsec_token 参数")
+	}
+	loadAll, _ := strconv.ParseBool(args["load_all_comments"])
+	logrus.Infof("MCP: 生成评论总结提示词 - Feed ID: %s, loadAllComments=%v", feedID, loadAll)
+
+	detail, err := s.xiaohongshuService.GetFeedDetail(ctx, feedID, xsecToken, loadAll)
+	if err != nil {
+		return nil, fmt.Errorf("获取笔记详情失败: %w", err)
+	}
+	if detail.Data == nil {
+		return nil, fmt.Errorf("获取笔记详情失败: 笔记数据为空")
+	}
+
+	note := detail.Data.Note
+	comments := detail.Data.Comments.List
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "请总结下面这篇小红书笔记的评论区。\n\n笔记「%s」- %s\n", note.Title, nickname(note.User))
+	fmt.Fprintf(&b, "点赞 %s，收藏 %s，评论 %s\n", note.InteractInfo.LikedCount, note.InteractInfo.CollectedCount, note.InteractInfo.CommentCount)
+	fmt.Fprintf(&b, "正文：\n%s\n\n", note.Desc)
+
+	fmt.Fprintf(&b, "评论（共读取 %d 条一级评论）：\n", len(comments))
+	for _, comment := range comments {
+		writePromptComment(&b, comment, "")
+		for _, reply := range comment.SubComments {
+			writePromptComment(&b, reply, "  ↳ ")
+		}
+	}
+	if len(comments) == 0 {
+		b.WriteString("（暂无评论）\n")
+	}
+
+	b.WriteString(`
+请输出：
+1. 评论区的主要观点，按出现频率排序
+2. 整体情绪倾向（正面、中性、负面的大致比例）及原因
+3. 读者的常见问题和诉求
+4. 值得作者回复的评论，附上 comment_id 和 user_id，便于使用 reply_comment_in_feed 工具回复
+5. 对作者后续内容的建议`)
+
+	return userPrompt(fmt.Sprintf("总结笔记「%s」的评论", note.Title), b.String()), nil
+}
+
+// writePromptComment 将一条评论写成一行，包含回复评论所需的 comment_id 和 user_id
+func writePromptComment(b *strings.Builder, comment xiaohongshu.Comment, prefix string) {
+	fmt.Fprintf(b, "%s- %s（点赞 %s", prefix, nickname(comment.UserInfo), comment.LikeCount)
+	if comment.IPLocation != "" {
+		fmt.Fprintf(b, "，%s", comment.IPLocation)
+	}
+	fmt.Fprintf(b, "，comment_id: %s，user_id: %s）：%s\n", comment.ID, comment.UserInfo.UserID, comment.Content)
+}
+
+func (s *AppServer) analyzeCreatorPrompt(ctx context.Context, args map[string]string) (*mcp.GetPromptResult, error) {
+	userID := strings.TrimSpace(args["user_id"])
+	xsecToken := strings.TrimSpace(args["xsec_token"])
+	if userID == "" || xsecToken == "" {
+		return nil, fmt.Errorf("生成提示词失败: 缺少 user_id 或 xsec_token 参数")
+	}
+	logrus.Infof("MCP: 生成博主分析提示词 - User ID: %s", userID)
+
+	profile, err := s.xiaohongshuService.UserProfile(ctx, userID, xsecToken, xiaohongshu.ProfileTabNotes, xiaohongshu.PageOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("获取用户主页失败: %w", err)
+	}
+
+	info := profile.UserBasicInfo
+	var b strings.Builder
+	b.WriteString("请分析下面这位小红书博主。\n\n")
+	fmt.Fprintf(&b, "昵称：%s\n小红书号：%s\nIP属地：%s\n简介：%s\n", info.Nickname, info.RedId, info.IpLocation, info.Desc)
+	for _, interaction := range profile.Interactions {
+		fmt.Fprintf(&b, "%s：%s\n", interaction.Name, interaction.Count)
+	}
+
+	fmt.Fprintf(&b, "\n主页笔记（共 %d 篇）：\n", len(profile.Feeds))
+	for i, feed := range profile.Feeds {
+		card := feed.NoteCard
+		kind := "图文"
+		if card.Type == "video" {
+			kind = "视频"
+		}
+		fmt.Fprintf(&b, "%d. [%s] %s（点赞 %s）\n", i+1, kind, card.DisplayTitle, card.InteractInfo.LikedCount)
+	}
+	if len(profile.Feeds) == 0 {
+		b.WriteString("（暂无公开笔记）\n")
+	}
+
+	b.WriteString(`
+请输出：
+1. 账号定位：领域、目标人群和人设特点
+2. 内容分析：主要选题、图文与视频的比例、标题风格
+3. 数据表现：点赞最高的笔记有哪些共同特征，粉丝与获赞的比例反映的互动水平
+4. 可借鉴之处和潜在不足
+5. 商业合作价值评估及合作建议`)
+
+	return userPrompt(fmt.Sprintf("分析博主 %s", info.Nickname), b.String()), nil
+}
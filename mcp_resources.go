@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"runtime/debug"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// MCP 资源类型，即资源 URI 的 host 部分，如 xhs://note/{feed_id}
+const (
+	resourceNote   = "note"
+	resourceUser   = "user"
+	resourceSearch = "search"
+)
+
+// resourceURI 解析后的资源 URI
+type resourceURI struct {
+	Kind  string
+	ID    string // 笔记ID、用户ID或搜索关键词
+	Query url.Values
+}
+
+// parseResourceURI 解析 xhs://{kind}/{id}?... 形式的资源 URI
+func parseResourceURI(raw string) (*resourceURI, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("资源 URI 无效: %w", err)
+	}
+	if u.Scheme != "xhs" {
+		return nil, fmt.Errorf("资源 URI 无效: %s", raw)
+	}
+
+	escaped := strings.TrimPrefix(u.EscapedPath(), "/")
+	if escaped == "" || strings.Contains(escaped, "/") {
+		return nil, fmt.Errorf("资源 URI 无效: %s", raw)
+	}
+	id, err := url.PathUnescape(escaped)
+	if err != nil {
+		return nil, fmt.Errorf("资源 URI 无效: %w", err)
+	}
+
+	switch u.Host {
+	case resourceNote, resourceUser, resourceSearch:
+	default:
+		return nil, fmt.Errorf("未知的资源类型: %s", u.Host)
+	}
+
+	return &resourceURI{Kind: u.Host, ID: id, Query: u.Query()}, nil
+}
+
+// resourceSubscriptions 客户端订阅的资源 URI 及订阅次数。
+// 写操作修改了笔记或用户后，据此找到需要通知的 URI。
+type resourceSubscriptions struct {
+	mu   sync.Mutex
+	uris map[string]int
+}
+
+func newResourceSubscriptions() *resourceSubscriptions {
+	return &resourceSubscriptions{uris: make(map[string]int)}
+}
+
+func (r *resourceSubscriptions) subscribe(_ context.Context, req *mcp.SubscribeRequest) error {
+	uri, err := parseResourceURI(req.Params.URI)
+	if err != nil {
+		return err
+	}
+	if uri.Kind == resourceSearch {
+		return fmt.Errorf("搜索结果不支持订阅: %s", req.Params.URI)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.uris[req.Params.URI]++
+	return nil
+}
+
+func (r *resourceSubscriptions) unsubscribe(_ context.Context, req *mcp.UnsubscribeRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.uris[req.Params.URI] <= 1 {
+		delete(r.uris, req.Params.URI)
+	} else {
+		r.uris[req.Params.URI]--
+	}
+	return nil
+}
+
+// matching 返回与变更相关的已订阅 URI
+func (r *resourceSubscriptions) matching(change Change) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var uris []string
+	for raw := range r.uris {
+		uri, err := parseResourceURI(raw)
+		if err != nil {
+			continue
+		}
+		if (uri.Kind == resourceNote && uri.ID == change.FeedID) ||
+			(uri.Kind == resourceUser && uri.ID == change.UserID) {
+			uris = append(uris, raw)
+		}
+	}
+	return uris
+}
+
+// notifyResourceChanged 通知订阅了被修改笔记或用户的客户端
+func (s *AppServer) notifyResourceChanged(change Change) {
+	for _, uri := range s.resourceSubs.matching(change) {
+		logrus.Infof("MCP: 资源已更新 %s", uri)
+		if err := s.mcpServer.ResourceUpdated(context.Background(), &mcp.ResourceUpdatedNotificationParams{URI: uri}); err != nil {
+			logrus.Warnf("发送资源更新通知失败: %s, %v", uri, err)
+		}
+	}
+}
+
+// registerResources 注册 MCP 资源模板
+func registerResources(server *mcp.Server, appServer *AppServer) {
+	resources := []struct {
+		template *mcp.ResourceTemplate
+		handler  mcp.ResourceHandler
+	}{
+		{
+			&mcp.ResourceTemplate{
+				URITemplate: "xhs://note/{feed_id}{?xsec_token,account}",
+				Name:        "note",
+				Title:       "小红书笔记",
+				Description: "笔记详情，包含内容、图片、作者、互动数据和前10条一级评论。xsec_token 从 Feed 列表或搜索结果获取。支持订阅，评论、点赞、收藏该笔记后收到更新通知",
+				MIMEType:    "application/json",
+			},
+			withResourceRecovery(appServer.readNoteResource),
+		},
+		{
+			&mcp.ResourceTemplate{
+				URITemplate: "xhs://user/{user_id}{?xsec_token,account}",
+				Name:        "user",
+				Title:       "小红书用户主页",
+				Description: "用户主页，包含基本信息、关注/粉丝/获赞数和首屏笔记。xsec_token 从 Feed 列表或搜索结果获取。支持订阅，关注或取消关注该用户后收到更新通知",
+				MIMEType:    "application/json",
+			},
+			withResourceRecovery(appServer.readUserResource),
+		},
+		{
+			&mcp.ResourceTemplate{
+				URITemplate: "xhs://search/{keyword}{?account}",
+				Name:        "search",
+				Title:       "小红书搜索结果",
+				Description: "关键词的首屏搜索结果（约20条），关键词需要 URL 编码，如 xhs://search/%E7%BE%8E%E9%A3%9F",
+				MIMEType:    "application/json",
+			},
+			withResourceRecovery(appServer.readSearchResource),
+		},
+	}
+
+	for _, r := range resources {
+		server.AddResourceTemplate(r.template, r.handler)
+	}
+	logrus.Infof("Registered %d MCP resource templates", len(resources))
+}
+
+// withResourceRecovery 捕获资源读取时的 panic，返回错误而不是中断连接
+func withResourceRecovery(handler func(context.Context, *resourceURI) (any, error)) mcp.ResourceHandler {
+	return func(ctx context.Context, req *mcp.ReadResourceRequest) (result *mcp.ReadResourceResult, err error) {
+		rawURI := req.Params.URI
+		defer func() {
+			if r := recover(); r != nil {
+				logrus.WithFields(logrus.Fields{
+					"uri":   rawURI,
+					"panic": r,
+				}).Error("Resource handler panicked")
+				logrus.Errorf("Stack trace:\n%s", debug.Stack())
+
+				result = nil
+				err = fmt.Errorf("读取资源 %s 时发生内部错误: %v", rawURI, r)
+			}
+		}()
+
+		uri, err := parseResourceURI(rawURI)
+		if err != nil {
+			return nil, err
+		}
+		ctx = accounts.WithAccount(ctx, uri.Query.Get("account"))
+
+		data, err := handler(ctx, uri)
+		if err != nil {
+			if myerrors.CodeOf(err) == myerrors.CodeNoteDeleted {
+				return nil, mcp.ResourceNotFoundError(rawURI)
+			}
+			return nil, err
+		}
+
+		jsonData, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("序列化资源失败: %w", err)
+		}
+
+		return &mcp.ReadResourceResult{
+			Contents: []*mcp.ResourceContents{{
+				URI:      rawURI,
+				MIMEType: "application/json",
+				Text:     string(jsonData),
+			}},
+		}, nil
+	}
+}
+
+func (s *AppServer) readNoteResource(ctx context.Context, uri *resourceURI) (any, error) {
+	logrus.Infof("MCP: 读取笔记资源 %s", uri.ID)
+
+	xsecToken := uri.Query.Get("xsec_token")
+	if xsecToken == "" {
+		return nil, fmt.Errorf("读取笔记失败: 缺少 xsec_token 参数")
+	}
+	return s.xiaohongshuService.GetFeedDetail(ctx, uri.ID, xsecToken, false)
+}
+
+func (s *AppServer) readUserResource(ctx context.Context, uri *resourceURI) (any, error) {
+	logrus.Infof("MCP: 读取用户资源 %s", uri.ID)
+
+	xsecToken := uri.Query.Get("xsec_token")
+	if xsecToken == "" {
+		return nil, fmt.Errorf("读取用户主页失败: 缺少 xsec_token 参数")
+	}
+	return s.xiaohongshuService.UserProfile(ctx, uri.ID, xsecToken, xiaohongshu.ProfileTabNotes, xiaohongshu.PageOptions{})
+}
+
+func (s *AppServer) readSearchResource(ctx context.Context, uri *resourceURI) (any, error) {
+	logrus.Infof("MCP: 读取搜索资源 %s", uri.ID)
+
+	return s.xiaohongshuService.SearchFeeds(ctx, uri.ID, xiaohongshu.PageOptions{})
+}
@@ -3,11 +3,21 @@ package main
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"maps"
+	"reflect"
 	"runtime/debug"
 
+	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/audit"
+	"github.com/xpzouying/xiaohongshu-mcp/jobs"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/progress"
+	"github.com/xpzouying/xiaohongshu-mcp/schedule"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 )
 
 // Helper functions for annotation pointers
@@ -15,26 +25,48 @@ func boolPtr(b bool) *bool { return &b }
 
 // MCP 工具参数结构体定义
 
+// AccountArgs 仅包含账号选择的参数
+type AccountArgs struct {
+	Account string `json:"account,omitempty" jsonschema:"账号名称（可选），不填使用默认账号，可通过 list_accounts 查看"`
+}
+
 // PublishContentArgs 发布内容的参数
 type PublishContentArgs struct {
-	Title   string   `json:"title" jsonschema:"内容标题（小红书限制：最多20个中文字或英文单词）"`
-	Content string   `json:"content" jsonschema:"正文内容，不包含以#开头的标签内容，所有话题标签都用tags参数来生成和提供即可"`
-	Images  []string `json:"images" jsonschema:"图片路径列表（至少需要1张图片）。支持两种方式：1. HTTP/HTTPS图片链接（自动下载）；2. 本地图片绝对路径（推荐，如:/Users/user/image.jpg）"`
-	Tags    []string `json:"tags,omitempty" jsonschema:"话题标签列表（可选参数），如 [美食, 旅行, 生活]"`
+	Title       string   `json:"title" jsonschema:"内容标题（小红书限制：最多20个中文字或英文单词）"`
+	Content     string   `json:"content" jsonschema:"正文内容，不包含以#开头的标签内容，所有话题标签都用tags参数来生成和提供即可"`
+	Images      []string `json:"images" jsonschema:"图片路径列表（至少需要1张图片）。支持两种方式：1. HTTP/HTTPS图片链接（自动下载）；2. 本地图片绝对路径（推荐，如:/Users/user/image.jpg）"`
+	Tags        []string `json:"tags,omitempty" jsonschema:"话题标签列表（可选参数），如 [美食, 旅行, 生活]"`
+	PublishAt   string   `json:"publish_at,omitempty" jsonschema:"定时发布时间（可选），RFC3339 格式，如 2025-01-01T20:00:00+08:00，不填立即发布"`
+	SaveAsDraft bool     `json:"save_as_draft,omitempty" jsonschema:"是否仅保存为草稿（可选），true 时填写内容后暂存到创作中心草稿箱而不发布，便于人工审核后再发布，不能与 publish_at 同时指定"`
+	Account     string   `json:"account,omitempty" jsonschema:"账号名称（可选），不填使用默认账号，可通过 list_accounts 查看"`
 }
 
 // PublishVideoArgs 发布视频的参数（仅支持本地单个视频文件）
 type PublishVideoArgs struct {
-	Title   string   `json:"title" jsonschema:"内容标题（小红书限制：最多20个中文字或英文单词）"`
-	Content string   `json:"content" jsonschema:"正文内容，不包含以#开头的标签内容，所有话题标签都用tags参数来生成和提供即可"`
-	Video   string   `json:"video" jsonschema:"本地视频绝对路径（仅支持单个视频文件，如:/Users/user/video.mp4）"`
-	Tags    []string `json:"tags,omitempty" jsonschema:"话题标签列表（可选参数），如 [美食, 旅行, 生活]"`
+	Title       string   `json:"title" jsonschema:"内容标题（小红书限制：最多20个中文字或英文单词）"`
+	Content     string   `json:"content" jsonschema:"正文内容，不包含以#开头的标签内容，所有话题标签都用tags参数来生成和提供即可"`
+	Video       string   `json:"video" jsonschema:"本地视频绝对路径（仅支持单个视频文件，如:/Users/user/video.mp4）"`
+	Tags        []string `json:"tags,omitempty" jsonschema:"话题标签列表（可选参数），如 [美食, 旅行, 生活]"`
+	PublishAt   string   `json:"publish_at,omitempty" jsonschema:"定时发布时间（可选），RFC3339 格式，如 2025-01-01T20:00:00+08:00，不填立即发布"`
+	SaveAsDraft bool     `json:"save_as_draft,omitempty" jsonschema:"是否仅保存为草稿（可选），true 时填写内容后暂存到创作中心草稿箱而不发布，便于人工审核后再发布，不能与 publish_at 同时指定"`
+	Account     string   `json:"account,omitempty" jsonschema:"账号名称（可选），不填使用默认账号，可通过 list_accounts 查看"`
+}
+
+// ListFeedsArgs 获取首页 Feeds 列表的参数
+type ListFeedsArgs struct {
+	Channel string `json:"channel,omitempty" jsonschema:"频道（可选），默认 recommend。可选 recommend|fashion|food|cosmetics|movie|career|love|home|gaming|travel|fitness"`
+	Limit   int    `json:"limit,omitempty" jsonschema:"数量（可选），指定后滚动加载直到凑够，不填只返回首屏内容"`
+	Cursor  string `json:"cursor,omitempty" jsonschema:"继续获取的游标（可选），传入上一次返回的 next_cursor 获取更多不重复的内容"`
+	Account string `json:"account,omitempty" jsonschema:"账号名称（可选），不填使用默认账号，可通过 list_accounts 查看"`
 }
 
 // SearchFeedsArgs 搜索内容的参数
 type SearchFeedsArgs struct {
 	Keyword string       `json:"keyword" jsonschema:"搜索关键词"`
 	Filters FilterOption `json:"filters,omitempty" jsonschema:"筛选选项"`
+	Limit   int          `json:"limit,omitempty" jsonschema:"每页数量（可选），指定后滚动加载直到凑够一页，不填只返回首屏约20条结果"`
+	Page    int          `json:"page,omitempty" jsonschema:"页码（可选），从1开始，配合limit使用。page*limit 不能超过1000"`
+	Account string       `json:"account,omitempty" jsonschema:"账号名称（可选），不填使用默认账号，可通过 list_accounts 查看"`
 }
 
 // FilterOption 筛选选项结构体
@@ -55,12 +87,17 @@ type FeedDetailArgs struct {
 	ClickMoreReplies bool   `json:"click_more_replies,omitempty" jsonschema:"【仅当load_all_comments为true时生效】是否展开二级回复。true展开子评论，false不展开（默认）"`
 	ReplyLimit       int    `json:"reply_limit,omitempty" jsonschema:"【仅当click_more_replies为true时生效】跳过回复数过多的评论。例如10表示跳过超过10条回复的，默认10"`
 	ScrollSpeed      string `json:"scroll_speed,omitempty" jsonschema:"【仅当load_all_comments为true时生效】滚动速度slow慢速、normal正常、fast快速"`
+	Account          string `json:"account,omitempty" jsonschema:"账号名称（可选），不填使用默认账号，可通过 list_accounts 查看"`
 }
 
 // UserProfileArgs 获取用户主页的参数
 type UserProfileArgs struct {
 	UserID    string `json:"user_id" jsonschema:"小红书用户ID，从Feed列表获取"`
 	XsecToken string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
+	Tab       string `json:"tab,omitempty" jsonschema:"标签页（可选），notes笔记、collected收藏、liked点赞，默认notes。收藏和点赞仅在用户公开时可见"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"每页笔记数量（可选），指定后滚动加载直到凑够一页，不填只返回首屏笔记"`
+	Page      int    `json:"page,omitempty" jsonschema:"页码（可选），从1开始，配合limit使用。page*limit 不能超过1000"`
+	Account   string `json:"account,omitempty" jsonschema:"账号名称（可选），不填使用默认账号，可通过 list_accounts 查看"`
 }
 
 // PostCommentArgs 发表评论的参数
@@ -68,6 +105,7 @@ type PostCommentArgs struct {
 	FeedID    string `json:"feed_id" jsonschema:"小红书笔记ID，从Feed列表获取"`
 	XsecToken string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
 	Content   string `json:"content" jsonschema:"评论内容"`
+	Account   string `json:"account,omitempty" jsonschema:"账号名称（可选），不填使用默认账号，可通过 list_accounts 查看"`
 }
 
 // ReplyCommentArgs 回复评论的参数
@@ -77,6 +115,7 @@ type ReplyCommentArgs struct {
 	CommentID string `json:"comment_id,omitempty" jsonschema:"目标评论ID，从评论列表获取"`
 	UserID    string `json:"user_id,omitempty" jsonschema:"目标评论用户ID，从评论列表获取"`
 	Content   string `json:"content" jsonschema:"回复内容"`
+	Account   string `json:"account,omitempty" jsonschema:"账号名称（可选），不填使用默认账号，可通过 list_accounts 查看"`
 }
 
 // LikeFeedArgs 点赞参数
@@ -84,6 +123,7 @@ type LikeFeedArgs struct {
 	FeedID    string `json:"feed_id" jsonschema:"小红书笔记ID，从Feed列表获取"`
 	XsecToken string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
 	Unlike    bool   `json:"unlike,omitempty" jsonschema:"是否取消点赞，true为取消点赞，false或未设置则为点赞"`
+	Account   string `json:"account,omitempty" jsonschema:"账号名称（可选），不填使用默认账号，可通过 list_accounts 查看"`
 }
 
 // FavoriteFeedArgs 收藏参数
@@ -91,6 +131,64 @@ type FavoriteFeedArgs struct {
 	FeedID     string `json:"feed_id" jsonschema:"小红书笔记ID，从Feed列表获取"`
 	XsecToken  string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
 	Unfavorite bool   `json:"unfavorite,omitempty" jsonschema:"是否取消收藏，true为取消收藏，false或未设置则为收藏"`
+	Account    string `json:"account,omitempty" jsonschema:"账号名称（可选），不填使用默认账号，可通过 list_accounts 查看"`
+}
+
+// FollowUserArgs 关注/取消关注参数
+type FollowUserArgs struct {
+	UserID    string `json:"user_id" jsonschema:"小红书用户ID，从Feed列表或用户主页获取"`
+	XsecToken string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
+	Account   string `json:"account,omitempty" jsonschema:"账号名称（可选），不填使用默认账号，可通过 list_accounts 查看"`
+}
+
+// BatchOperationArgs 批量互动中的一项操作
+type BatchOperationArgs struct {
+	Action    string `json:"action" jsonschema:"操作类型: like|unlike|favorite|unfavorite|comment|follow|unfollow"`
+	FeedID    string `json:"feed_id,omitempty" jsonschema:"笔记ID，点赞、收藏、评论操作必填"`
+	UserID    string `json:"user_id,omitempty" jsonschema:"用户ID，关注、取消关注操作必填"`
+	XsecToken string `json:"xsec_token" jsonschema:"访问令牌，从Feed列表的xsecToken字段获取"`
+	Content   string `json:"content,omitempty" jsonschema:"评论内容，comment 操作必填"`
+}
+
+// BatchInteractArgs 批量互动参数
+type BatchInteractArgs struct {
+	Operations []BatchOperationArgs `json:"operations" jsonschema:"操作列表，最多50项，按顺序依次执行"`
+	Account    string               `json:"account,omitempty" jsonschema:"账号名称（可选），不填使用默认账号，可通过 list_accounts 查看"`
+}
+
+// JobArgs 异步任务参数
+type JobArgs struct {
+	JobID string `json:"job_id" jsonschema:"任务ID，由 publish_content 或 publish_with_video 返回"`
+}
+
+// ListDraftsArgs 查询草稿箱参数
+type ListDraftsArgs struct {
+	Type    string `json:"type,omitempty" jsonschema:"草稿类型: image|video，默认 image"`
+	Account string `json:"account,omitempty" jsonschema:"账号名称（可选），不填使用默认账号，可通过 list_accounts 查看"`
+}
+
+// DraftArgs 定位草稿的参数
+type DraftArgs struct {
+	Type    string `json:"type,omitempty" jsonschema:"草稿类型: image|video，默认 image"`
+	Index   int    `json:"index" jsonschema:"草稿在草稿箱中的位置，从 0 开始，从 list_drafts 获取"`
+	Title   string `json:"title,omitempty" jsonschema:"草稿标题（可选，推荐），用于校验草稿，避免草稿箱变化后操作到其他草稿"`
+	Account string `json:"account,omitempty" jsonschema:"账号名称（可选），不填使用默认账号，可通过 list_accounts 查看"`
+}
+
+// ListSchedulesArgs 查询定时发布任务参数
+type ListSchedulesArgs struct {
+	Status string `json:"status,omitempty" jsonschema:"按状态筛选（可选）: scheduled|running|done|failed|canceled，不填返回全部"`
+}
+
+// RescheduleArgs 修改定时发布时间参数
+type RescheduleArgs struct {
+	TaskID    string `json:"task_id" jsonschema:"定时任务ID，由定时发布或 list_scheduled_publishes 返回"`
+	PublishAt string `json:"publish_at" jsonschema:"新的发布时间，RFC3339 格式，如 2025-01-01T20:00:00+08:00"`
+}
+
+// ScheduleTaskArgs 定时任务参数
+type ScheduleTaskArgs struct {
+	TaskID string `json:"task_id" jsonschema:"定时任务ID，由定时发布或 list_scheduled_publishes 返回"`
 }
 
 // InitMCPServer 初始化 MCP Server
@@ -101,11 +199,16 @@ func InitMCPServer(appServer *AppServer) *mcp.Server {
 			Name:    "xiaohongshu-mcp",
 			Version: "2.0.0",
 		},
-		nil,
+		&mcp.ServerOptions{
+			SubscribeHandler:   appServer.resourceSubs.subscribe,
+			UnsubscribeHandler: appServer.resourceSubs.unsubscribe,
+		},
 	)
 
-	// 注册所有工具
+	// 注册所有工具、资源和提示词
 	registerTools(server, appServer)
+	registerResources(server, appServer)
+	registerPrompts(server, appServer)
 
 	logrus.Info("MCP Server initialized with official SDK")
 
@@ -140,126 +243,192 @@ func withPanicRecovery[T any](
 			}
 		}()
 
+		ctx = audit.WithSource(ctx, audit.Source{Channel: audit.ChannelMCP, Name: toolName, Client: mcpClientName(req)})
+		ctx = withProgressNotifications(ctx, req)
 		return handler(ctx, req, args)
 	}
 }
 
+// withProgressNotifications 客户端在请求中提供了 progressToken 时，
+// 将执行过程中上报的进度（如已加载的评论数）以 notifications/progress 发送给客户端
+func withProgressNotifications(ctx context.Context, req *mcp.CallToolRequest) context.Context {
+	if req == nil || req.Session == nil || req.Params == nil {
+		return ctx
+	}
+	token := req.Params.GetProgressToken()
+	if token == nil {
+		return ctx
+	}
+
+	return progress.WithReporter(ctx, func(current, total int, message string) {
+		params := &mcp.ProgressNotificationParams{
+			ProgressToken: token,
+			Progress:      float64(current),
+			Total:         float64(total),
+			Message:       message,
+		}
+		if err := req.Session.NotifyProgress(ctx, params); err != nil {
+			logrus.Warnf("发送进度通知失败: %v", err)
+		}
+	})
+}
+
+// mcpClientName 返回发起调用的 MCP 客户端名称和版本
+func mcpClientName(req *mcp.CallToolRequest) string {
+	if req == nil || req.Session == nil {
+		return ""
+	}
+	params := req.Session.InitializeParams()
+	if params == nil || params.ClientInfo == nil {
+		return ""
+	}
+	info := params.ClientInfo
+	if info.Version == "" {
+		return info.Name
+	}
+	return info.Name + "/" + info.Version
+}
+
+// addTool 注册工具并累加 count，用于启动日志中的工具数量
+func addTool[In, Out any](server *mcp.Server, count *int, tool *mcp.Tool, handler mcp.ToolHandlerFor[In, Out]) {
+	mcp.AddTool(server, tool, handler)
+	*count++
+}
+
 // registerTools 注册所有 MCP 工具
 func registerTools(server *mcp.Server, appServer *AppServer) {
+	count := 0
+
 	// 工具 1: 检查登录状态
-	mcp.AddTool(server,
+	addTool(server, &count,
 		&mcp.Tool{
-			Name:        "check_login_status",
-			Description: "检查小红书登录状态",
+			Name:         "check_login_status",
+			Description:  "检查小红书登录状态",
+			OutputSchema: outputSchema[LoginStatusResponse](),
 			Annotations: &mcp.ToolAnnotations{
 				Title:        "Check Login Status",
 				ReadOnlyHint: true,
 			},
 		},
-		withPanicRecovery("check_login_status", func(ctx context.Context, req *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, any, error) {
+		withPanicRecovery("check_login_status", func(ctx context.Context, req *mcp.CallToolRequest, args AccountArgs) (*mcp.CallToolResult, any, error) {
+			ctx = accounts.WithAccount(ctx, args.Account)
 			result := appServer.handleCheckLoginStatus(ctx)
-			return convertToMCPResult(result), nil, nil
+			return convertToMCPResult(result), result.Structured, nil
 		}),
 	)
 
 	// 工具 2: 获取登录二维码
-	mcp.AddTool(server,
+	addTool(server, &count,
 		&mcp.Tool{
-			Name:        "get_login_qrcode",
-			Description: "获取登录二维码（返回 Base64 图片和超时时间）",
+			Name:         "get_login_qrcode",
+			Description:  "获取登录二维码（返回 Base64 图片和超时时间）",
+			OutputSchema: outputSchema[LoginQrcodeResponse](),
 			Annotations: &mcp.ToolAnnotations{
 				Title:        "Get Login QR Code",
 				ReadOnlyHint: true,
 			},
 		},
-		withPanicRecovery("get_login_qrcode", func(ctx context.Context, req *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, any, error) {
+		withPanicRecovery("get_login_qrcode", func(ctx context.Context, req *mcp.CallToolRequest, args AccountArgs) (*mcp.CallToolResult, any, error) {
+			ctx = accounts.WithAccount(ctx, args.Account)
 			result := appServer.handleGetLoginQrcode(ctx)
-			return convertToMCPResult(result), nil, nil
+			return convertToMCPResult(result), result.Structured, nil
 		}),
 	)
 
 	// 工具 3: 删除 cookies（登录重置）
-	mcp.AddTool(server,
+	addTool(server, &count,
 		&mcp.Tool{
-			Name:        "delete_cookies",
-			Description: "删除 cookies 文件，重置登录状态。删除后需要重新登录。",
+			Name:         "delete_cookies",
+			Description:  "删除 cookies 文件，重置登录状态。删除后需要重新登录。",
+			OutputSchema: outputSchema[DeleteCookiesResponse](),
 			Annotations: &mcp.ToolAnnotations{
 				Title:           "Delete Cookies",
 				DestructiveHint: boolPtr(true),
 			},
 		},
-		withPanicRecovery("delete_cookies", func(ctx context.Context, req *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, any, error) {
+		withPanicRecovery("delete_cookies", func(ctx context.Context, req *mcp.CallToolRequest, args AccountArgs) (*mcp.CallToolResult, any, error) {
+			ctx = accounts.WithAccount(ctx, args.Account)
 			result := appServer.handleDeleteCookies(ctx)
-			return convertToMCPResult(result), nil, nil
+			return convertToMCPResult(result), result.Structured, nil
 		}),
 	)
 
 	// 工具 4: 发布内容
-	mcp.AddTool(server,
+	addTool(server, &count,
 		&mcp.Tool{
-			Name:        "publish_content",
-			Description: "发布小红书图文内容",
+			Name:         "publish_content",
+			Description:  "发布小红书图文内容。发布在后台执行，立即返回任务ID，使用 get_job 查询进度；指定 publish_at 时加入定时发布队列",
+			OutputSchema: outputSchema[PublishSubmitResponse](),
 			Annotations: &mcp.ToolAnnotations{
 				Title:           "Publish Content",
 				DestructiveHint: boolPtr(true),
 			},
 		},
 		withPanicRecovery("publish_content", func(ctx context.Context, req *mcp.CallToolRequest, args PublishContentArgs) (*mcp.CallToolResult, any, error) {
+			ctx = accounts.WithAccount(ctx, args.Account)
 			// 转换参数格式到现有的 handler
 			argsMap := map[string]interface{}{
-				"title":   args.Title,
-				"content": args.Content,
-				"images":  convertStringsToInterfaces(args.Images),
-				"tags":    convertStringsToInterfaces(args.Tags),
+				"title":         args.Title,
+				"content":       args.Content,
+				"images":        convertStringsToInterfaces(args.Images),
+				"tags":          convertStringsToInterfaces(args.Tags),
+				"publish_at":    args.PublishAt,
+				"save_as_draft": args.SaveAsDraft,
 			}
 			result := appServer.handlePublishContent(ctx, argsMap)
-			return convertToMCPResult(result), nil, nil
+			return convertToMCPResult(result), result.Structured, nil
 		}),
 	)
 
 	// 工具 5: 获取Feed列表
-	mcp.AddTool(server,
+	addTool(server, &count,
 		&mcp.Tool{
-			Name:        "list_feeds",
-			Description: "获取首页 Feeds 列表",
+			Name:         "list_feeds",
+			Description:  "获取首页 Feeds 列表。可指定频道和数量，返回的 next_cursor 可用于继续获取更多内容",
+			OutputSchema: outputSchema[FeedsListResponse](),
 			Annotations: &mcp.ToolAnnotations{
 				Title:        "List Feeds",
 				ReadOnlyHint: true,
 			},
 		},
-		withPanicRecovery("list_feeds", func(ctx context.Context, req *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, any, error) {
-			result := appServer.handleListFeeds(ctx)
-			return convertToMCPResult(result), nil, nil
+		withPanicRecovery("list_feeds", func(ctx context.Context, req *mcp.CallToolRequest, args ListFeedsArgs) (*mcp.CallToolResult, any, error) {
+			ctx = accounts.WithAccount(ctx, args.Account)
+			result := appServer.handleListFeeds(ctx, args)
+			return convertToMCPResult(result), result.Structured, nil
 		}),
 	)
 
 	// 工具 6: 搜索内容
-	mcp.AddTool(server,
+	addTool(server, &count,
 		&mcp.Tool{
-			Name:        "search_feeds",
-			Description: "搜索小红书内容（需要已登录）",
+			Name:         "search_feeds",
+			Description:  "搜索小红书内容（需要已登录）。指定 limit 和 page 可滚动加载更多结果，返回 has_more 表示是否还有下一页",
+			OutputSchema: outputSchema[FeedsListResponse](),
 			Annotations: &mcp.ToolAnnotations{
 				Title:        "Search Feeds",
 				ReadOnlyHint: true,
 			},
 		},
 		withPanicRecovery("search_feeds", func(ctx context.Context, req *mcp.CallToolRequest, args SearchFeedsArgs) (*mcp.CallToolResult, any, error) {
+			ctx = accounts.WithAccount(ctx, args.Account)
 			result := appServer.handleSearchFeeds(ctx, args)
-			return convertToMCPResult(result), nil, nil
+			return convertToMCPResult(result), result.Structured, nil
 		}),
 	)
 
 	// 工具 7: 获取Feed详情
-	mcp.AddTool(server,
+	addTool(server, &count,
 		&mcp.Tool{
-			Name:        "get_feed_detail",
-			Description: "获取小红书笔记详情，返回笔记内容、图片、作者信息、互动数据（点赞/收藏/分享数）及评论列表。默认返回前10条一级评论，如需更多评论请设置load_all_comments=true",
+			Name:         "get_feed_detail",
+			Description:  "获取小红书笔记详情，返回笔记内容、图片、作者信息、互动数据（点赞/收藏/分享数）及评论列表。默认返回前10条一级评论，如需更多评论请设置load_all_comments=true",
+			OutputSchema: outputSchema[FeedDetailResponse](),
 			Annotations: &mcp.ToolAnnotations{
 				Title:        "Get Feed Detail",
 				ReadOnlyHint: true,
 			},
 		},
 		withPanicRecovery("get_feed_detail", func(ctx context.Context, req *mcp.CallToolRequest, args FeedDetailArgs) (*mcp.CallToolResult, any, error) {
+			ctx = accounts.WithAccount(ctx, args.Account)
 			argsMap := map[string]interface{}{
 				"feed_id":           args.FeedID,
 				"xsec_token":        args.XsecToken,
@@ -290,62 +459,71 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 			}
 
 			result := appServer.handleGetFeedDetail(ctx, argsMap)
-			return convertToMCPResult(result), nil, nil
+			return convertToMCPResult(result), result.Structured, nil
 		}),
 	)
 
 	// 工具 8: 获取用户主页
-	mcp.AddTool(server,
+	addTool(server, &count,
 		&mcp.Tool{
-			Name:        "user_profile",
-			Description: "获取指定的小红书用户主页，返回用户基本信息，关注、粉丝、获赞量及其笔记内容",
+			Name:         "user_profile",
+			Description:  "获取指定的小红书用户主页，返回用户基本信息，关注、粉丝、获赞量及其笔记内容。指定 limit 和 page 可滚动加载更多笔记，tab 可选择收藏、点赞标签页",
+			OutputSchema: outputSchema[UserProfileResponse](),
 			Annotations: &mcp.ToolAnnotations{
 				Title:        "User Profile",
 				ReadOnlyHint: true,
 			},
 		},
 		withPanicRecovery("user_profile", func(ctx context.Context, req *mcp.CallToolRequest, args UserProfileArgs) (*mcp.CallToolResult, any, error) {
+			ctx = accounts.WithAccount(ctx, args.Account)
 			argsMap := map[string]interface{}{
 				"user_id":    args.UserID,
 				"xsec_token": args.XsecToken,
+				"tab":        args.Tab,
+				"limit":      args.Limit,
+				"page":       args.Page,
 			}
 			result := appServer.handleUserProfile(ctx, argsMap)
-			return convertToMCPResult(result), nil, nil
+			return convertToMCPResult(result), result.Structured, nil
 		}),
 	)
 
 	// 工具 9: 发表评论
-	mcp.AddTool(server,
+	addTool(server, &count,
 		&mcp.Tool{
-			Name:        "post_comment_to_feed",
-			Description: "发表评论到小红书笔记",
+			Name:         "post_comment_to_feed",
+			Description:  "发表评论到小红书笔记",
+			OutputSchema: outputSchema[PostCommentResponse](),
 			Annotations: &mcp.ToolAnnotations{
 				Title:           "Post Comment",
 				DestructiveHint: boolPtr(true),
 			},
 		},
 		withPanicRecovery("post_comment_to_feed", func(ctx context.Context, req *mcp.CallToolRequest, args PostCommentArgs) (*mcp.CallToolResult, any, error) {
+			ctx = accounts.WithAccount(ctx, args.Account)
 			argsMap := map[string]interface{}{
 				"feed_id":    args.FeedID,
 				"xsec_token": args.XsecToken,
 				"content":    args.Content,
 			}
 			result := appServer.handlePostComment(ctx, argsMap)
-			return convertToMCPResult(result), nil, nil
+			return convertToMCPResult(result), result.Structured, nil
 		}),
 	)
 
 	// 工具 10: 回复评论
-	mcp.AddTool(server,
+	addTool(server, &count,
 		&mcp.Tool{
-			Name:        "reply_comment_in_feed",
-			Description: "回复小红书笔记下的指定评论",
+			Name:         "reply_comment_in_feed",
+			Description:  "回复小红书笔记下的指定评论",
+			OutputSchema: outputSchema[ReplyCommentResponse](),
 			Annotations: &mcp.ToolAnnotations{
 				Title:           "Reply Comment",
 				DestructiveHint: boolPtr(true),
 			},
 		},
-		func(ctx context.Context, req *mcp.CallToolRequest, args ReplyCommentArgs) (*mcp.CallToolResult, any, error) {
+		withPanicRecovery("reply_comment_in_feed", func(ctx context.Context, req *mcp.CallToolRequest, args ReplyCommentArgs) (*mcp.CallToolResult, any, error) {
+			ctx = accounts.WithAccount(ctx, args.Account)
 			if args.CommentID == "" && args.UserID == "" {
 				return &mcp.CallToolResult{
 					IsError: true,
@@ -361,75 +539,329 @@ func registerTools(server *mcp.Server, appServer *AppServer) {
 				"content":    args.Content,
 			}
 			result := appServer.handleReplyComment(ctx, argsMap)
-			return convertToMCPResult(result), nil, nil
-		},
+			return convertToMCPResult(result), result.Structured, nil
+		}),
 	)
 
 	// 工具 11: 发布视频（仅本地文件）
-	mcp.AddTool(server,
+	addTool(server, &count,
 		&mcp.Tool{
-			Name:        "publish_with_video",
-			Description: "发布小红书视频内容（仅支持本地单个视频文件）",
+			Name:         "publish_with_video",
+			Description:  "发布小红书视频内容（仅支持本地单个视频文件）。发布在后台执行，立即返回任务ID，使用 get_job 查询进度；指定 publish_at 时加入定时发布队列",
+			OutputSchema: outputSchema[PublishSubmitResponse](),
 			Annotations: &mcp.ToolAnnotations{
 				Title:           "Publish Video",
 				DestructiveHint: boolPtr(true),
 			},
 		},
 		withPanicRecovery("publish_with_video", func(ctx context.Context, req *mcp.CallToolRequest, args PublishVideoArgs) (*mcp.CallToolResult, any, error) {
+			ctx = accounts.WithAccount(ctx, args.Account)
 			argsMap := map[string]interface{}{
-				"title":   args.Title,
-				"content": args.Content,
-				"video":   args.Video,
-				"tags":    convertStringsToInterfaces(args.Tags),
+				"title":         args.Title,
+				"content":       args.Content,
+				"video":         args.Video,
+				"tags":          convertStringsToInterfaces(args.Tags),
+				"publish_at":    args.PublishAt,
+				"save_as_draft": args.SaveAsDraft,
 			}
 			result := appServer.handlePublishVideo(ctx, argsMap)
-			return convertToMCPResult(result), nil, nil
+			return convertToMCPResult(result), result.Structured, nil
 		}),
 	)
 
 	// 工具 12: 点赞笔记
-	mcp.AddTool(server,
+	addTool(server, &count,
 		&mcp.Tool{
-			Name:        "like_feed",
-			Description: "为指定笔记点赞或取消点赞（如已点赞将跳过点赞，如未点赞将跳过取消点赞）",
+			Name:         "like_feed",
+			Description:  "为指定笔记点赞或取消点赞（如已点赞将跳过点赞，如未点赞将跳过取消点赞）",
+			OutputSchema: outputSchema[ActionResult](),
 			Annotations: &mcp.ToolAnnotations{
 				Title:           "Like Feed",
 				DestructiveHint: boolPtr(true),
 			},
 		},
 		withPanicRecovery("like_feed", func(ctx context.Context, req *mcp.CallToolRequest, args LikeFeedArgs) (*mcp.CallToolResult, any, error) {
+			ctx = accounts.WithAccount(ctx, args.Account)
 			argsMap := map[string]interface{}{
 				"feed_id":    args.FeedID,
 				"xsec_token": args.XsecToken,
 				"unlike":     args.Unlike,
 			}
 			result := appServer.handleLikeFeed(ctx, argsMap)
-			return convertToMCPResult(result), nil, nil
+			return convertToMCPResult(result), result.Structured, nil
 		}),
 	)
 
 	// 工具 13: 收藏笔记
-	mcp.AddTool(server,
+	addTool(server, &count,
 		&mcp.Tool{
-			Name:        "favorite_feed",
-			Description: "收藏指定笔记或取消收藏（如已收藏将跳过收藏，如未收藏将跳过取消收藏）",
+			Name:         "favorite_feed",
+			Description:  "收藏指定笔记或取消收藏（如已收藏将跳过收藏，如未收藏将跳过取消收藏）",
+			OutputSchema: outputSchema[ActionResult](),
 			Annotations: &mcp.ToolAnnotations{
 				Title:           "Favorite Feed",
 				DestructiveHint: boolPtr(true),
 			},
 		},
 		withPanicRecovery("favorite_feed", func(ctx context.Context, req *mcp.CallToolRequest, args FavoriteFeedArgs) (*mcp.CallToolResult, any, error) {
+			ctx = accounts.WithAccount(ctx, args.Account)
 			argsMap := map[string]interface{}{
 				"feed_id":    args.FeedID,
 				"xsec_token": args.XsecToken,
 				"unfavorite": args.Unfavorite,
 			}
 			result := appServer.handleFavoriteFeed(ctx, argsMap)
-			return convertToMCPResult(result), nil, nil
+			return convertToMCPResult(result), result.Structured, nil
+		}),
+	)
+
+	// 工具 14: 列出账号
+	addTool(server, &count,
+		&mcp.Tool{
+			Name:         "list_accounts",
+			Description:  "列出已配置的小红书账号。其他工具可通过 account 参数指定使用哪个账号",
+			OutputSchema: outputSchema[AccountsListResponse](),
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List Accounts",
+				ReadOnlyHint: true,
+			},
+		},
+		withPanicRecovery("list_accounts", func(ctx context.Context, req *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleListAccounts(ctx)
+			return convertToMCPResult(result), result.Structured, nil
+		}),
+	)
+
+	// 工具 15: 查询异步任务
+	addTool(server, &count,
+		&mcp.Tool{
+			Name:         "get_job",
+			Description:  "查询发布任务的进度，阶段依次为 pending、downloading、uploading、filling、submitting，最终为 done、failed 或 canceled",
+			OutputSchema: outputSchema[jobs.Job](),
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Get Job",
+				ReadOnlyHint: true,
+			},
+		},
+		withPanicRecovery("get_job", func(ctx context.Context, req *mcp.CallToolRequest, args JobArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleGetJob(ctx, args)
+			return convertToMCPResult(result), result.Structured, nil
+		}),
+	)
+
+	// 工具 16: 取消异步任务
+	addTool(server, &count,
+		&mcp.Tool{
+			Name:         "cancel_job",
+			Description:  "取消尚未完成的发布任务",
+			OutputSchema: outputSchema[jobs.Job](),
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Cancel Job",
+				DestructiveHint: boolPtr(true),
+			},
+		},
+		withPanicRecovery("cancel_job", func(ctx context.Context, req *mcp.CallToolRequest, args JobArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleCancelJob(ctx, args)
+			return convertToMCPResult(result), result.Structured, nil
+		}),
+	)
+
+	// 工具 17: 查询定时发布任务
+	addTool(server, &count,
+		&mcp.Tool{
+			Name:         "list_scheduled_publishes",
+			Description:  "查询定时发布队列中的任务，包括发布时间、状态、尝试次数和失败原因",
+			OutputSchema: outputSchema[SchedulesListResponse](),
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List Scheduled Publishes",
+				ReadOnlyHint: true,
+			},
+		},
+		withPanicRecovery("list_scheduled_publishes", func(ctx context.Context, req *mcp.CallToolRequest, args ListSchedulesArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleListSchedules(ctx, args)
+			return convertToMCPResult(result), result.Structured, nil
+		}),
+	)
+
+	// 工具 18: 修改定时发布时间
+	addTool(server, &count,
+		&mcp.Tool{
+			Name:         "reschedule_publish",
+			Description:  "修改定时发布任务的发布时间，已失败的任务改期后会重新尝试",
+			OutputSchema: outputSchema[schedule.Task](),
+			Annotations: &mcp.ToolAnnotations{
+				Title: "Reschedule Publish",
+			},
+		},
+		withPanicRecovery("reschedule_publish", func(ctx context.Context, req *mcp.CallToolRequest, args RescheduleArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleReschedule(ctx, args)
+			return convertToMCPResult(result), result.Structured, nil
+		}),
+	)
+
+	// 工具 19: 取消定时发布
+	addTool(server, &count,
+		&mcp.Tool{
+			Name:         "cancel_scheduled_publish",
+			Description:  "取消定时发布任务",
+			OutputSchema: outputSchema[schedule.Task](),
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Cancel Scheduled Publish",
+				DestructiveHint: boolPtr(true),
+			},
+		},
+		withPanicRecovery("cancel_scheduled_publish", func(ctx context.Context, req *mcp.CallToolRequest, args ScheduleTaskArgs) (*mcp.CallToolResult, any, error) {
+			result := appServer.handleCancelSchedule(ctx, args)
+			return convertToMCPResult(result), result.Structured, nil
+		}),
+	)
+
+	// 工具 20: 查询草稿箱
+	addTool(server, &count,
+		&mcp.Tool{
+			Name:         "list_drafts",
+			Description:  "查询创作中心草稿箱中的草稿，返回每条草稿的位置和标题",
+			OutputSchema: outputSchema[DraftsListResponse](),
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "List Drafts",
+				ReadOnlyHint: true,
+			},
+		},
+		withPanicRecovery("list_drafts", func(ctx context.Context, req *mcp.CallToolRequest, args ListDraftsArgs) (*mcp.CallToolResult, any, error) {
+			ctx = accounts.WithAccount(ctx, args.Account)
+			result := appServer.handleListDrafts(ctx, args)
+			return convertToMCPResult(result), result.Structured, nil
 		}),
 	)
 
-	logrus.Infof("Registered %d MCP tools", 13)
+	// 工具 21: 打开草稿
+	addTool(server, &count,
+		&mcp.Tool{
+			Name:         "open_draft",
+			Description:  "打开草稿箱中的草稿，返回草稿的标题和正文",
+			OutputSchema: outputSchema[xiaohongshu.DraftContent](),
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Open Draft",
+				ReadOnlyHint: true,
+			},
+		},
+		withPanicRecovery("open_draft", func(ctx context.Context, req *mcp.CallToolRequest, args DraftArgs) (*mcp.CallToolResult, any, error) {
+			ctx = accounts.WithAccount(ctx, args.Account)
+			result := appServer.handleOpenDraft(ctx, args)
+			return convertToMCPResult(result), result.Structured, nil
+		}),
+	)
+
+	// 工具 22: 发布草稿
+	addTool(server, &count,
+		&mcp.Tool{
+			Name:         "publish_draft",
+			Description:  "发布草稿箱中的草稿。发布在后台执行，立即返回任务ID，使用 get_job 查询进度",
+			OutputSchema: outputSchema[jobs.Job](),
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Publish Draft",
+				DestructiveHint: boolPtr(true),
+			},
+		},
+		withPanicRecovery("publish_draft", func(ctx context.Context, req *mcp.CallToolRequest, args DraftArgs) (*mcp.CallToolResult, any, error) {
+			ctx = accounts.WithAccount(ctx, args.Account)
+			result := appServer.handlePublishDraft(ctx, args)
+			return convertToMCPResult(result), result.Structured, nil
+		}),
+	)
+
+	// 工具 23: 关注用户
+	addTool(server, &count,
+		&mcp.Tool{
+			Name:         "follow_user",
+			Description:  "关注指定用户（如已关注将跳过），返回 changed 表示本次是否实际改变了关注状态",
+			OutputSchema: outputSchema[xiaohongshu.FollowResult](),
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Follow User",
+				DestructiveHint: boolPtr(true),
+				IdempotentHint:  true,
+			},
+		},
+		withPanicRecovery("follow_user", func(ctx context.Context, req *mcp.CallToolRequest, args FollowUserArgs) (*mcp.CallToolResult, any, error) {
+			ctx = accounts.WithAccount(ctx, args.Account)
+			result := appServer.handleFollowUser(ctx, args, true)
+			return convertToMCPResult(result), result.Structured, nil
+		}),
+	)
+
+	// 工具 24: 取消关注用户
+	addTool(server, &count,
+		&mcp.Tool{
+			Name:         "unfollow_user",
+			Description:  "取消关注指定用户（如未关注将跳过），返回 changed 表示本次是否实际改变了关注状态",
+			OutputSchema: outputSchema[xiaohongshu.FollowResult](),
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Unfollow User",
+				DestructiveHint: boolPtr(true),
+				IdempotentHint:  true,
+			},
+		},
+		withPanicRecovery("unfollow_user", func(ctx context.Context, req *mcp.CallToolRequest, args FollowUserArgs) (*mcp.CallToolResult, any, error) {
+			ctx = accounts.WithAccount(ctx, args.Account)
+			result := appServer.handleFollowUser(ctx, args, false)
+			return convertToMCPResult(result), result.Structured, nil
+		}),
+	)
+
+	// 工具 25: 批量互动
+	addTool(server, &count,
+		&mcp.Tool{
+			Name:         "batch_interact",
+			Description:  "在同一个浏览器页面上批量执行点赞、收藏、评论、关注等操作，操作之间有随机间隔。每项操作单独返回结果，某一项失败不影响其他操作",
+			OutputSchema: outputSchema[BatchInteractResponse](),
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Batch Interact",
+				DestructiveHint: boolPtr(true),
+			},
+		},
+		withPanicRecovery("batch_interact", func(ctx context.Context, req *mcp.CallToolRequest, args BatchInteractArgs) (*mcp.CallToolResult, any, error) {
+			ctx = accounts.WithAccount(ctx, args.Account)
+			result := appServer.handleBatchInteract(ctx, args)
+			return convertToMCPResult(result), result.Structured, nil
+		}),
+	)
+
+	// 工具 26: 查看账号遇到的验证码
+	addTool(server, &count,
+		&mcp.Tool{
+			Name:         "get_account_challenge",
+			Description:  "查看账号遇到的验证码（原因、页面地址和截图）。账号遇到验证码后会暂停使用，其他工具返回 CAPTCHA_REQUIRED，需要人工完成验证后调用 resume_account 恢复",
+			OutputSchema: outputSchema[AccountChallengeResponse](),
+			Annotations: &mcp.ToolAnnotations{
+				Title:        "Get Account Challenge",
+				ReadOnlyHint: true,
+			},
+		},
+		withPanicRecovery("get_account_challenge", func(ctx context.Context, req *mcp.CallToolRequest, args AccountArgs) (*mcp.CallToolResult, any, error) {
+			ctx = accounts.WithAccount(ctx, args.Account)
+			result := appServer.handleGetAccountChallenge(ctx)
+			return convertToMCPResult(result), result.Structured, nil
+		}),
+	)
+
+	// 工具 27: 恢复暂停的账号
+	addTool(server, &count,
+		&mcp.Tool{
+			Name:         "resume_account",
+			Description:  "人工完成验证码后恢复账号，浏览器会重新加载 cookies",
+			OutputSchema: outputSchema[ResumeAccountResponse](),
+			Annotations: &mcp.ToolAnnotations{
+				Title: "Resume Account",
+			},
+		},
+		withPanicRecovery("resume_account", func(ctx context.Context, req *mcp.CallToolRequest, args AccountArgs) (*mcp.CallToolResult, any, error) {
+			ctx = accounts.WithAccount(ctx, args.Account)
+			result := appServer.handleResumeAccount(ctx)
+			return convertToMCPResult(result), result.Structured, nil
+		}),
+	)
+
+	logrus.Infof("Registered %d MCP tools", count)
 }
 
 // convertToMCPResult 将自定义的 MCPToolResult 转换为官方 SDK 的格式
@@ -457,10 +889,69 @@ func convertToMCPResult(result *MCPToolResult) *mcp.CallToolResult {
 		}
 	}
 
-	return &mcp.CallToolResult{
+	callResult := &mcp.CallToolResult{
 		Content: contents,
 		IsError: result.IsError,
 	}
+	if result.Code != "" || result.ArtifactID != "" {
+		callResult.Meta = mcp.Meta{}
+	}
+	if result.Code != "" {
+		callResult.Meta["code"] = result.Code
+	}
+	if result.ArtifactID != "" {
+		callResult.Meta["artifact_id"] = result.ArtifactID
+	}
+	return callResult
+}
+
+// outputSchema 根据工具返回的 Go 类型生成 OutputSchema。
+// 在 jsonschema.For 推导结果的基础上做了调整，使其与 encoding/json 的实际输出一致：
+//   - []byte 序列化为 Base64 字符串，json.RawMessage 可以是任意 JSON
+//   - nil 切片和 map 序列化为 null，因此数组和对象字典都允许为 null
+//   - 评论的子评论与评论同类型，jsonschema 无法处理循环引用，子评论只校验为对象
+func outputSchema[T any]() *jsonschema.Schema {
+	schema, err := jsonschema.For[T](&jsonschema.ForOptions{TypeSchemas: outputTypeSchemas()})
+	if err != nil {
+		panic(fmt.Sprintf("生成输出结构失败: %v", err))
+	}
+	allowNullCollections(schema)
+	return schema
+}
+
+func outputTypeSchemas() map[reflect.Type]*jsonschema.Schema {
+	schemas := map[reflect.Type]*jsonschema.Schema{
+		reflect.TypeFor[[]byte]():          {Types: []string{"null", "string"}},
+		reflect.TypeFor[json.RawMessage](): {},
+	}
+
+	subComments := map[reflect.Type]*jsonschema.Schema{
+		reflect.TypeFor[[]xiaohongshu.Comment](): {Type: "array", Items: &jsonschema.Schema{Type: "object"}},
+	}
+	maps.Copy(subComments, schemas)
+	comment, err := jsonschema.For[xiaohongshu.Comment](&jsonschema.ForOptions{TypeSchemas: subComments})
+	if err != nil {
+		panic(fmt.Sprintf("生成评论输出结构失败: %v", err))
+	}
+	schemas[reflect.TypeFor[xiaohongshu.Comment]()] = comment
+
+	return schemas
+}
+
+// allowNullCollections 递归地将 array 和 additionalProperties 形式的 object 改为允许 null
+func allowNullCollections(s *jsonschema.Schema) {
+	if s == nil {
+		return
+	}
+	if s.Type == "array" || (s.Type == "object" && s.AdditionalProperties != nil && s.Properties == nil) {
+		s.Types = []string{"null", s.Type}
+		s.Type = ""
+	}
+	for _, prop := range s.Properties {
+		allowNullCollections(prop)
+	}
+	allowNullCollections(s.Items)
+	allowNullCollections(s.AdditionalProperties)
 }
 
 // convertStringsToInterfaces 辅助函数：将 []string 转换为 []interface{}
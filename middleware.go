@@ -5,6 +5,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/audit"
 )
 
 // corsMiddleware CORS 中间件
@@ -12,7 +14,7 @@ func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Account")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusNoContent)
@@ -32,3 +34,42 @@ func errorHandlingMiddleware() gin.HandlerFunc {
 			"服务器内部错误", recovered)
 	})
 }
+
+// accountMiddleware 解析请求使用的账号，写入 request context。
+// 通过 query 参数 account 或请求头 X-Account 指定，未指定时使用默认账号。
+func (s *AppServer) accountMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Query("account")
+		if name == "" {
+			name = c.GetHeader("X-Account")
+		}
+
+		account, err := s.accounts.Get(name)
+		if err != nil {
+			respondError(c, http.StatusNotFound, "ACCOUNT_NOT_FOUND",
+				"账号不存在", err.Error())
+			c.Abort()
+			return
+		}
+
+		c.Set("account", account.Name)
+		c.Request = c.Request.WithContext(accounts.WithAccount(c.Request.Context(), account.Name))
+
+		c.Next()
+	}
+}
+
+// auditSourceMiddleware 在 request context 中记录调用的接口和客户端，写操作的审计日志据此记录来源
+func auditSourceMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		source := audit.Source{
+			Channel: audit.ChannelHTTP,
+			Name:    c.Request.Method + " " + c.FullPath(),
+			Client:  c.Request.UserAgent(),
+			Remote:  c.ClientIP(),
+		}
+		c.Request = c.Request.WithContext(audit.WithSource(c.Request.Context(), source))
+
+		c.Next()
+	}
+}
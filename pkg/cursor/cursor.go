@@ -0,0 +1,98 @@
+package cursor
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultTTL 游标默认有效期
+	DefaultTTL = 30 * time.Minute
+
+	// 最多保留的游标数量，超过时淘汰最早过期的
+	maxEntries = 1000
+)
+
+// ErrInvalidCursor 游标不存在或已过期
+var ErrInvalidCursor = errors.New("游标无效或已过期，请重新从第一页获取")
+
+type entry[T any] struct {
+	state     T
+	expiresAt time.Time
+}
+
+// Store 在内存中保存翻页状态，对外只暴露不透明的游标字符串。
+// 游标只能使用到过期为止，服务重启后全部失效。
+type Store[T any] struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]entry[T]
+}
+
+// NewStore 创建游标存储
+func NewStore[T any](ttl time.Duration) *Store[T] {
+	return &Store[T]{
+		ttl:     ttl,
+		entries: make(map[string]entry[T]),
+	}
+}
+
+// Put 保存翻页状态并返回新的游标
+func (s *Store[T]) Put(state T) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.evict(now)
+
+	token := newToken()
+	s.entries[token] = entry[T]{state: state, expiresAt: now.Add(s.ttl)}
+	return token
+}
+
+// Get 读取游标对应的翻页状态
+func (s *Store[T]) Get(token string) (T, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[token]
+	if !ok || time.Now().After(e.expiresAt) {
+		delete(s.entries, token)
+		var zero T
+		return zero, ErrInvalidCursor
+	}
+
+	return e.state, nil
+}
+
+// evict 清理过期游标，数量仍超限时淘汰最早过期的
+func (s *Store[T]) evict(now time.Time) {
+	for token, e := range s.entries {
+		if now.After(e.expiresAt) {
+			delete(s.entries, token)
+		}
+	}
+
+	for len(s.entries) >= maxEntries {
+		oldest := ""
+		for token, e := range s.entries {
+			if oldest == "" || e.expiresAt.Before(s.entries[oldest].expiresAt) {
+				oldest = token
+			}
+		}
+		delete(s.entries, oldest)
+	}
+}
+
+func newToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return time.Now().Format("20060102150405.000000000")
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,38 @@
+package cursor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	s := NewStore[[]string](time.Minute)
+
+	token := s.Put([]string{"a", "b"})
+	require.NotEmpty(t, token)
+
+	state, err := s.Get(token)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, state)
+
+	// 每次保存都生成新的游标，旧游标仍然可用
+	next := s.Put([]string{"a", "b", "c"})
+	require.NotEqual(t, token, next)
+	_, err = s.Get(token)
+	require.NoError(t, err)
+
+	_, err = s.Get("unknown")
+	require.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestStoreExpire(t *testing.T) {
+	s := NewStore[int](time.Millisecond)
+
+	token := s.Put(1)
+	time.Sleep(5 * time.Millisecond)
+
+	_, err := s.Get(token)
+	require.ErrorIs(t, err, ErrInvalidCursor)
+}
@@ -0,0 +1,22 @@
+// Package progress 通过 context 上报耗时操作的进度，如已加载的评论数、已上传的图片数。
+// 调用方（MCP 请求、异步任务）在 ctx 中设置回调，执行方调用 Report 上报，互不依赖。
+package progress
+
+import "context"
+
+// Func 进度回调。current 为已完成的数量，total 为总数（未知时为 0），message 为进度说明
+type Func func(current, total int, message string)
+
+type reporterKey struct{}
+
+// WithReporter 返回设置了进度回调的 ctx，覆盖 ctx 中已有的回调
+func WithReporter(ctx context.Context, fn Func) context.Context {
+	return context.WithValue(ctx, reporterKey{}, fn)
+}
+
+// Report 上报进度。ctx 中没有回调时不做任何事
+func Report(ctx context.Context, current, total int, message string) {
+	if fn, ok := ctx.Value(reporterKey{}).(Func); ok && fn != nil {
+		fn(current, total, message)
+	}
+}
@@ -0,0 +1,27 @@
+package progress
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport(t *testing.T) {
+	// 没有回调时不做任何事
+	Report(context.Background(), 1, 2, "")
+
+	var got []int
+	ctx := WithReporter(context.Background(), func(current, total int, message string) {
+		got = append(got, current, total)
+	})
+	Report(ctx, 1, 3, "已上传 1/3 张图片")
+	require.Equal(t, []int{1, 3}, got)
+
+	// 内层回调覆盖外层
+	var inner int
+	ctx = WithReporter(ctx, func(current, total int, message string) { inner = current })
+	Report(ctx, 2, 3, "")
+	require.Equal(t, 2, inner)
+	require.Equal(t, []int{1, 3}, got)
+}
@@ -0,0 +1,162 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
+)
+
+// Action 受限制的写操作类型
+type Action string
+
+const (
+	ActionPublish   Action = "publish"
+	ActionSaveDraft Action = "save_draft"
+	ActionComment   Action = "comment"
+	ActionReply     Action = "reply"
+	ActionLike      Action = "like"     // 点赞与取消点赞
+	ActionFavorite  Action = "favorite" // 收藏与取消收藏
+	ActionFollow    Action = "follow"   // 关注与取消关注
+)
+
+// 触发限制的原因
+const (
+	ReasonRate  = "rate"        // 超过频率限制
+	ReasonDaily = "daily_quota" // 超过每日配额
+)
+
+// Rule 单个操作类型的限制。
+// 频率限制使用令牌桶：每分钟补充 PerMinute 个令牌，最多累积 Burst 个。
+// PerMinute 为 0 时不限制频率，Daily 为 0 时不限制每日次数。
+type Rule struct {
+	PerMinute float64 `json:"per_minute"`
+	Burst     int     `json:"burst"`
+	Daily     int     `json:"daily"`
+}
+
+// Config 各操作类型的限制，未配置的操作类型不受限制。
+// 默认值和配置文件中的 rate_limits 由 configs 包提供。
+type Config map[Action]Rule
+
+// LimitError 操作被限制，RetryAfter 后可以重试
+type LimitError struct {
+	Account    string
+	Action     Action
+	Reason     string
+	RetryAfter time.Duration
+}
+
+func (e *LimitError) Error() string {
+	if e.Reason == ReasonDaily {
+		return fmt.Sprintf("账号 %s 今日 %s 操作次数已达上限，请在 %s 后重试", e.Account, e.Action, e.RetryAfter.Round(time.Second))
+	}
+	return fmt.Sprintf("账号 %s 的 %s 操作过于频繁，请在 %s 后重试", e.Account, e.Action, e.RetryAfter.Round(time.Second))
+}
+
+// ErrorCode 限流错误的错误代码
+func (e *LimitError) ErrorCode() myerrors.Code {
+	return myerrors.CodeRateLimited
+}
+
+// RetryAfterSeconds 向上取整的重试等待秒数
+func (e *LimitError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+type key struct {
+	account string
+	action  Action
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+type counter struct {
+	day   string
+	count int
+}
+
+// Limiter 按账号和操作类型限制写操作的频率和每日次数。
+// 计数保存在内存中，服务重启后重新计算。
+type Limiter struct {
+	cfg Config
+	now func() time.Time
+
+	mu       sync.Mutex
+	buckets  map[key]*bucket
+	counters map[key]*counter
+}
+
+// NewLimiter 创建限流器
+func NewLimiter(cfg Config) *Limiter {
+	return &Limiter{
+		cfg:      cfg,
+		now:      time.Now,
+		buckets:  make(map[key]*bucket),
+		counters: make(map[key]*counter),
+	}
+}
+
+// Allow 检查并占用一次操作额度，超过限制时返回 *LimitError
+func (l *Limiter) Allow(account string, action Action) error {
+	rule, ok := l.cfg[action]
+	if !ok {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	k := key{account: account, action: action}
+
+	c := l.counters[k]
+	if c == nil || c.day != dayOf(now) {
+		c = &counter{day: dayOf(now)}
+		l.counters[k] = c
+	}
+	if rule.Daily > 0 && c.count >= rule.Daily {
+		return &LimitError{Account: account, Action: action, Reason: ReasonDaily, RetryAfter: untilTomorrow(now)}
+	}
+
+	if rule.PerMinute > 0 {
+		burst := float64(rule.Burst)
+		if burst < 1 {
+			burst = 1
+		}
+
+		b := l.buckets[k]
+		if b == nil {
+			b = &bucket{tokens: burst, updated: now}
+			l.buckets[k] = b
+		}
+
+		perSecond := rule.PerMinute / 60
+		b.tokens = math.Min(burst, b.tokens+now.Sub(b.updated).Seconds()*perSecond)
+		b.updated = now
+
+		if b.tokens < 1 {
+			wait := time.Duration((1 - b.tokens) / perSecond * float64(time.Second))
+			return &LimitError{Account: account, Action: action, Reason: ReasonRate, RetryAfter: wait}
+		}
+		b.tokens--
+	}
+
+	c.count++
+	return nil
+}
+
+func dayOf(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// untilTomorrow 距离次日零点的时长
+func untilTomorrow(now time.Time) time.Duration {
+	y, m, d := now.Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, now.Location()).Sub(now)
+}
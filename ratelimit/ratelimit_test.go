@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
+)
+
+func newTestLimiter(cfg Config, now *time.Time) *Limiter {
+	l := NewLimiter(cfg)
+	l.now = func() time.Time { return *now }
+	return l
+}
+
+func TestLimiterRate(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.Local)
+	l := newTestLimiter(Config{ActionComment: {PerMinute: 1, Burst: 2}}, &now)
+
+	require.NoError(t, l.Allow("a", ActionComment))
+	require.NoError(t, l.Allow("a", ActionComment))
+
+	err := l.Allow("a", ActionComment)
+	var limitErr *LimitError
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, ReasonRate, limitErr.Reason)
+	require.Equal(t, 60, limitErr.RetryAfterSeconds())
+	require.Equal(t, myerrors.CodeRateLimited, myerrors.CodeOf(fmt.Errorf("评论失败: %w", err)))
+
+	// 其他账号和操作类型互不影响
+	require.NoError(t, l.Allow("b", ActionComment))
+	require.NoError(t, l.Allow("a", ActionLike))
+
+	now = now.Add(time.Minute)
+	require.NoError(t, l.Allow("a", ActionComment))
+}
+
+func TestLimiterDaily(t *testing.T) {
+	now := time.Date(2025, 1, 1, 23, 0, 0, 0, time.Local)
+	l := newTestLimiter(Config{ActionLike: {Daily: 2}}, &now)
+
+	require.NoError(t, l.Allow("a", ActionLike))
+	require.NoError(t, l.Allow("a", ActionLike))
+
+	err := l.Allow("a", ActionLike)
+	var limitErr *LimitError
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, ReasonDaily, limitErr.Reason)
+	require.Equal(t, time.Hour, limitErr.RetryAfter)
+
+	// 次日重新计数
+	now = now.Add(time.Hour)
+	require.NoError(t, l.Allow("a", ActionLike))
+}
@@ -37,21 +37,55 @@ func setupRoutes(appServer *AppServer) *gin.Engine {
 
 	// API 路由组
 	api := router.Group("/api/v1")
+	api.Use(appServer.accountMiddleware())
+	api.Use(auditSourceMiddleware())
 	{
 		api.GET("/login/status", appServer.checkLoginStatusHandler)
 		api.GET("/login/qrcode", appServer.getLoginQrcodeHandler)
 		api.DELETE("/login/cookies", appServer.deleteCookiesHandler)
 		api.POST("/publish", appServer.publishHandler)
 		api.POST("/publish_video", appServer.publishVideoHandler)
+		api.GET("/jobs/:id", appServer.getJobHandler)
+		api.DELETE("/jobs/:id", appServer.cancelJobHandler)
+		api.GET("/drafts", appServer.listDraftsHandler)
+		api.POST("/drafts", appServer.saveDraftHandler)
+		api.POST("/drafts/video", appServer.saveVideoDraftHandler)
+		api.GET("/drafts/:type/:index", appServer.openDraftHandler)
+		api.POST("/drafts/:type/:index/publish", appServer.publishDraftHandler)
+		api.GET("/schedules", appServer.listSchedulesHandler)
+		api.GET("/schedules/:id", appServer.getScheduleHandler)
+		api.PUT("/schedules/:id", appServer.rescheduleHandler)
+		api.DELETE("/schedules/:id", appServer.cancelScheduleHandler)
 		api.POST("/content/publish", appServer.publishContentHandler) // 新增的简化发布接口
 		api.GET("/feeds/list", appServer.listFeedsHandler)
 		api.GET("/feeds/search", appServer.searchFeedsHandler)
 		api.POST("/feeds/search", appServer.searchFeedsHandler)
 		api.POST("/feeds/detail", appServer.getFeedDetailHandler)
 		api.POST("/user/profile", appServer.userProfileHandler)
+		api.POST("/user/follow", appServer.followUserHandler)
+		api.POST("/user/unfollow", appServer.unfollowUserHandler)
 		api.POST("/feeds/comment", appServer.postCommentHandler)
 		api.POST("/feeds/comment/reply", appServer.replyCommentHandler)
+		api.POST("/feeds/like", appServer.likeFeedHandler)
+		api.POST("/feeds/unlike", appServer.unlikeFeedHandler)
+		api.POST("/feeds/favorite", appServer.favoriteFeedHandler)
+		api.POST("/feeds/unfavorite", appServer.unfavoriteFeedHandler)
+		api.POST("/feeds/like/batch", appServer.batchLikeFeedHandler)
+		api.POST("/feeds/unlike/batch", appServer.batchUnlikeFeedHandler)
+		api.POST("/feeds/favorite/batch", appServer.batchFavoriteFeedHandler)
+		api.POST("/feeds/unfavorite/batch", appServer.batchUnfavoriteFeedHandler)
+		api.POST("/batch", appServer.batchInteractHandler)
 		api.GET("/user/me", appServer.myProfileHandler)
+		api.GET("/accounts", appServer.listAccountsHandler)
+		api.POST("/accounts", appServer.addAccountHandler)
+		api.DELETE("/accounts/:name", appServer.removeAccountHandler)
+		api.GET("/accounts/:name/challenge", appServer.getAccountChallengeHandler)
+		api.GET("/accounts/:name/challenge/screenshot", appServer.getAccountChallengeScreenshotHandler)
+		api.POST("/accounts/:name/resume", appServer.resumeAccountHandler)
+		api.GET("/audit", appServer.queryAuditHandler)
+		api.GET("/debug/artifacts/:id", appServer.getArtifactHandler)
+		api.GET("/debug/artifacts/:id/:file", appServer.getArtifactFileHandler)
+		api.GET("/config", appServer.getConfigHandler)
 	}
 
 	return router
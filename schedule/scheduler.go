@@ -0,0 +1,447 @@
+package schedule
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Status 定时任务状态
+type Status string
+
+const (
+	StatusScheduled Status = "scheduled" // 等待执行（含失败后等待重试）
+	StatusRunning   Status = "running"
+	StatusDone      Status = "done"
+	StatusFailed    Status = "failed" // 重试次数用尽
+	StatusCanceled  Status = "canceled"
+)
+
+const (
+	defaultPollInterval = 10 * time.Second
+	defaultRetryDelay   = 5 * time.Minute
+	defaultMaxAttempts  = 3
+)
+
+var (
+	ErrTaskNotFound = errors.New("定时任务不存在")
+	ErrTaskFinished = errors.New("定时任务已结束")
+	ErrTaskRunning  = errors.New("定时任务正在执行")
+	ErrPastTime     = errors.New("发布时间必须晚于当前时间")
+)
+
+// noRetryError 标记不应重试的错误
+type noRetryError struct{ err error }
+
+func (e noRetryError) Error() string { return e.err.Error() }
+func (e noRetryError) Unwrap() error { return e.err }
+
+// NoRetry 包装执行错误，任务失败后直接结束而不再重试
+func NoRetry(err error) error {
+	if err == nil {
+		return nil
+	}
+	return noRetryError{err: err}
+}
+
+// Task 定时发布任务
+type Task struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	Account     string          `json:"account"`
+	Payload     json.RawMessage `json:"payload"`
+	PublishAt   time.Time       `json:"publish_at"`
+	NextRunAt   time.Time       `json:"next_run_at"`
+	Status      Status          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	LastError   string          `json:"last_error,omitempty"`
+	Result      json.RawMessage `json:"result,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty"`
+}
+
+// Finished 任务是否已结束
+func (t Task) Finished() bool {
+	return t.Status == StatusDone || t.Status == StatusFailed || t.Status == StatusCanceled
+}
+
+// RunFunc 执行到期任务
+type RunFunc func(ctx context.Context, task Task) (any, error)
+
+// Config 调度器配置，未设置（为 0）的配置项使用默认值
+type Config struct {
+	// PollInterval 扫描到期任务的间隔
+	PollInterval time.Duration
+	// RetryDelay 失败后的重试间隔，第 n 次失败后等待 n 倍
+	RetryDelay time.Duration
+	// MaxAttempts 每个任务最多尝试的次数
+	MaxAttempts int
+}
+
+// Scheduler 定时发布调度器。
+// 定期扫描到期任务并执行，失败后按递增间隔重试，直到达到最大尝试次数。
+type Scheduler struct {
+	store *Store
+	run   RunFunc
+
+	pollInterval time.Duration
+	retryDelay   time.Duration
+	maxAttempts  int
+
+	mu      sync.Mutex
+	running map[string]context.CancelFunc
+
+	wake      chan struct{}
+	stop      chan struct{}
+	stopOnce  sync.Once
+	startOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// NewScheduler 创建调度器，需调用 Start 后才会执行任务
+func NewScheduler(store *Store, run RunFunc, cfg Config) *Scheduler {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	if cfg.RetryDelay <= 0 {
+		cfg.RetryDelay = defaultRetryDelay
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+
+	return &Scheduler{
+		store:        store,
+		run:          run,
+		pollInterval: cfg.PollInterval,
+		retryDelay:   cfg.RetryDelay,
+		maxAttempts:  cfg.MaxAttempts,
+		running:      make(map[string]context.CancelFunc),
+		wake:         make(chan struct{}, 1),
+		stop:         make(chan struct{}),
+	}
+}
+
+// Start 启动调度循环。上次退出时仍在执行的任务视为一次失败的尝试。
+func (s *Scheduler) Start() {
+	s.startOnce.Do(func() {
+		s.recoverInterrupted()
+
+		s.wg.Add(1)
+		go s.loop()
+	})
+}
+
+// Stop 停止调度并取消正在执行的任务
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+
+		s.mu.Lock()
+		for _, cancel := range s.running {
+			cancel()
+		}
+		s.mu.Unlock()
+
+		s.wg.Wait()
+	})
+}
+
+// Add 添加定时任务
+func (s *Scheduler) Add(taskType, account string, payload any, publishAt time.Time) (Task, error) {
+	if !publishAt.After(time.Now()) {
+		return Task{}, ErrPastTime
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Task{}, errors.Wrap(err, "failed to marshal payload")
+	}
+
+	now := time.Now()
+	task := Task{
+		ID:          newID(),
+		Type:        taskType,
+		Account:     account,
+		Payload:     data,
+		PublishAt:   publishAt,
+		NextRunAt:   publishAt,
+		Status:      StatusScheduled,
+		MaxAttempts: s.maxAttempts,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	s.mu.Lock()
+	err = s.store.Put(task)
+	s.mu.Unlock()
+	if err != nil {
+		return Task{}, err
+	}
+
+	logrus.Infof("添加定时任务: id=%s, type=%s, account=%s, publish_at=%s",
+		task.ID, taskType, account, publishAt.Format(time.RFC3339))
+	s.notify()
+	return task, nil
+}
+
+// Get 获取任务
+func (s *Scheduler) Get(id string) (Task, error) {
+	return s.store.Get(id)
+}
+
+// List 返回任务列表，按发布时间排序。status 为空时返回全部。
+func (s *Scheduler) List(status Status) ([]Task, error) {
+	all, err := s.store.List()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]Task, 0, len(all))
+	for _, task := range all {
+		if status == "" || task.Status == status {
+			list = append(list, task)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].PublishAt.Before(list[j].PublishAt) })
+
+	return list, nil
+}
+
+// Reschedule 修改发布时间。重试次数用尽的任务重新排期后会重置尝试次数。
+func (s *Scheduler) Reschedule(id string, publishAt time.Time) (Task, error) {
+	if !publishAt.After(time.Now()) {
+		return Task{}, ErrPastTime
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task, err := s.store.Get(id)
+	if err != nil {
+		return Task{}, err
+	}
+
+	switch task.Status {
+	case StatusRunning:
+		return task, ErrTaskRunning
+	case StatusDone, StatusCanceled:
+		return task, ErrTaskFinished
+	case StatusFailed:
+		task.Attempts = 0
+		task.FinishedAt = nil
+	}
+
+	task.Status = StatusScheduled
+	task.PublishAt = publishAt
+	task.NextRunAt = publishAt
+	task.UpdatedAt = time.Now()
+
+	if err := s.store.Put(task); err != nil {
+		return Task{}, err
+	}
+
+	logrus.Infof("定时任务改期: id=%s, publish_at=%s", id, publishAt.Format(time.RFC3339))
+	s.notify()
+	return task, nil
+}
+
+// Cancel 取消任务。正在执行的任务会被中断。
+func (s *Scheduler) Cancel(id string) (Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task, err := s.store.Get(id)
+	if err != nil {
+		return Task{}, err
+	}
+	if task.Finished() {
+		return task, ErrTaskFinished
+	}
+
+	if cancel, ok := s.running[id]; ok {
+		cancel()
+	}
+
+	s.finish(&task, StatusCanceled)
+	task.LastError = "任务已取消"
+	if err := s.store.Put(task); err != nil {
+		return Task{}, err
+	}
+
+	logrus.Infof("取消定时任务: id=%s", id)
+	return task, nil
+}
+
+func (s *Scheduler) loop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.dispatchDue()
+
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// dispatchDue 启动所有到期任务
+func (s *Scheduler) dispatchDue() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, err := s.store.List()
+	if err != nil {
+		logrus.Errorf("读取定时任务失败: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, task := range list {
+		if task.Status != StatusScheduled || task.NextRunAt.After(now) {
+			continue
+		}
+
+		// 先记录尝试次数再执行，进程中途退出也会计入
+		task.Status = StatusRunning
+		task.Attempts++
+		task.UpdatedAt = now
+		if err := s.store.Put(task); err != nil {
+			logrus.Errorf("更新定时任务失败: id=%s, %v", task.ID, err)
+			continue
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		s.running[task.ID] = cancel
+
+		s.wg.Add(1)
+		go s.execute(ctx, task)
+	}
+}
+
+func (s *Scheduler) execute(ctx context.Context, task Task) {
+	defer s.wg.Done()
+
+	logrus.Infof("执行定时任务: id=%s, type=%s, attempt=%d/%d", task.ID, task.Type, task.Attempts, task.MaxAttempts)
+
+	result, err := func() (result any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = errors.Errorf("任务执行时发生内部错误: %v", r)
+			}
+		}()
+		return s.run(ctx, task)
+	}()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cancel, ok := s.running[task.ID]; ok {
+		cancel()
+		delete(s.running, task.ID)
+	}
+
+	// 以存储中的状态为准，执行期间可能已被取消
+	current, getErr := s.store.Get(task.ID)
+	if getErr != nil {
+		logrus.Errorf("读取定时任务失败: id=%s, %v", task.ID, getErr)
+		return
+	}
+	if current.Status != StatusRunning {
+		return
+	}
+
+	if err != nil {
+		s.fail(&current, err)
+	} else {
+		if data, marshalErr := json.Marshal(result); marshalErr == nil {
+			current.Result = data
+		}
+		current.LastError = ""
+		s.finish(&current, StatusDone)
+		logrus.Infof("定时任务完成: id=%s", task.ID)
+	}
+
+	if err := s.store.Put(current); err != nil {
+		logrus.Errorf("更新定时任务失败: id=%s, %v", task.ID, err)
+	}
+}
+
+// fail 记录一次失败，未达到最大尝试次数时安排重试
+func (s *Scheduler) fail(task *Task, err error) {
+	task.LastError = err.Error()
+
+	var noRetry noRetryError
+	if errors.As(err, &noRetry) || task.Attempts >= task.MaxAttempts {
+		s.finish(task, StatusFailed)
+		logrus.Errorf("定时任务失败，不再重试: id=%s, attempts=%d, %v", task.ID, task.Attempts, err)
+		return
+	}
+
+	now := time.Now()
+	task.Status = StatusScheduled
+	task.NextRunAt = now.Add(s.retryDelay * time.Duration(task.Attempts))
+	task.UpdatedAt = now
+	logrus.Warnf("定时任务失败，将于 %s 重试: id=%s, %v", task.NextRunAt.Format(time.RFC3339), task.ID, err)
+}
+
+func (s *Scheduler) finish(task *Task, status Status) {
+	now := time.Now()
+	task.Status = status
+	task.UpdatedAt = now
+	task.FinishedAt = &now
+}
+
+// recoverInterrupted 处理上次退出时未执行完的任务
+func (s *Scheduler) recoverInterrupted() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, err := s.store.List()
+	if err != nil {
+		logrus.Errorf("读取定时任务失败: %v", err)
+		return
+	}
+
+	for _, task := range list {
+		if task.Status != StatusRunning {
+			continue
+		}
+
+		// 中断时可能已经点击了发布，重试可能导致重复发布，需要人工确认
+		s.fail(&task, NoRetry(errors.New("服务重启，任务执行被中断，可能已经发布，请到创作中心确认")))
+		if err := s.store.Put(task); err != nil {
+			logrus.Errorf("更新定时任务失败: id=%s, %v", task.ID, err)
+		}
+	}
+}
+
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return time.Now().Format("20060102150405.000000000")
+	}
+	return hex.EncodeToString(b)
+}
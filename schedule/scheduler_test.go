@@ -0,0 +1,122 @@
+package schedule
+
+import (
+	"context"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestScheduler(t *testing.T, path string, run RunFunc) *Scheduler {
+	t.Helper()
+
+	store, err := OpenStore(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+
+	return NewScheduler(store, run, Config{
+		PollInterval: 10 * time.Millisecond,
+		RetryDelay:   10 * time.Millisecond,
+	})
+}
+
+func waitStatus(t *testing.T, s *Scheduler, id string, status Status) Task {
+	t.Helper()
+
+	var task Task
+	require.Eventually(t, func() bool {
+		var err error
+		task, err = s.Get(id)
+		require.NoError(t, err)
+		return task.Status == status
+	}, 2*time.Second, 10*time.Millisecond)
+
+	return task
+}
+
+func TestSchedulerRetry(t *testing.T) {
+	var calls atomic.Int32
+	s := newTestScheduler(t, filepath.Join(t.TempDir(), "schedule.db"), func(ctx context.Context, task Task) (any, error) {
+		if calls.Add(1) < 2 {
+			return nil, errors.New("网络错误")
+		}
+		return map[string]string{"status": "ok"}, nil
+	})
+	s.Start()
+	defer s.Stop()
+
+	_, err := s.Add("publish", "default", map[string]string{"title": "t"}, time.Now().Add(-time.Second))
+	require.ErrorIs(t, err, ErrPastTime)
+
+	task, err := s.Add("publish", "default", map[string]string{"title": "t"}, time.Now().Add(50*time.Millisecond))
+	require.NoError(t, err)
+	require.Equal(t, StatusScheduled, task.Status)
+
+	task = waitStatus(t, s, task.ID, StatusDone)
+	require.Equal(t, 2, task.Attempts)
+	require.JSONEq(t, `{"status":"ok"}`, string(task.Result))
+
+	_, err = s.Cancel(task.ID)
+	require.ErrorIs(t, err, ErrTaskFinished)
+}
+
+func TestSchedulerPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedule.db")
+	run := func(ctx context.Context, task Task) (any, error) { return nil, nil }
+
+	s := newTestScheduler(t, path, run)
+	task, err := s.Add("publish_video", "brand-a", map[string]string{"video": "/tmp/a.mp4"}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	later := time.Now().Add(2 * time.Hour)
+	task, err = s.Reschedule(task.ID, later)
+	require.NoError(t, err)
+	require.True(t, task.PublishAt.Equal(later))
+	require.NoError(t, s.store.Close())
+
+	// 重新打开后任务仍在队列中
+	s2 := newTestScheduler(t, path, run)
+	list, err := s2.List(StatusScheduled)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "brand-a", list[0].Account)
+
+	canceled, err := s2.Cancel(task.ID)
+	require.NoError(t, err)
+	require.Equal(t, StatusCanceled, canceled.Status)
+
+	_, err = s2.Reschedule(task.ID, later)
+	require.ErrorIs(t, err, ErrTaskFinished)
+
+	_, err = s2.Get("missing")
+	require.ErrorIs(t, err, ErrTaskNotFound)
+}
+
+func TestSchedulerInterruptedNotRetried(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedule.db")
+	var calls atomic.Int32
+	s := newTestScheduler(t, path, func(ctx context.Context, task Task) (any, error) {
+		calls.Add(1)
+		return nil, nil
+	})
+
+	task, err := s.Add("publish", "default", map[string]string{"title": "t"}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	// 模拟上次退出时任务正在执行
+	task.Status = StatusRunning
+	task.Attempts = 1
+	require.NoError(t, s.store.Put(task))
+
+	s.Start()
+	defer s.Stop()
+
+	task = waitStatus(t, s, task.ID, StatusFailed)
+	require.Equal(t, 1, task.Attempts)
+	require.Contains(t, task.LastError, "服务重启")
+	require.Zero(t, calls.Load())
+}
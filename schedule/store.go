@@ -0,0 +1,91 @@
+package schedule
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+var tasksBucket = []byte("tasks")
+
+// Store 基于 BoltDB 的定时任务存储，服务重启后任务不丢失
+type Store struct {
+	db *bolt.DB
+}
+
+// OpenStore 打开（或创建）定时任务数据库
+func OpenStore(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open schedule db %s", path)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(tasksBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, errors.Wrap(err, "failed to init schedule db")
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close 关闭数据库
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Put 保存任务
+func (s *Store) Put(task Task) error {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal task")
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(tasksBucket).Put([]byte(task.ID), data)
+	})
+}
+
+// Get 读取任务
+func (s *Store) Get(id string) (Task, error) {
+	var task Task
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(tasksBucket).Get([]byte(id))
+		if data == nil {
+			return errors.Wrap(ErrTaskNotFound, id)
+		}
+		return json.Unmarshal(data, &task)
+	})
+	return task, err
+}
+
+// List 读取全部任务
+func (s *Store) List() ([]Task, error) {
+	var list []Task
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(tasksBucket).ForEach(func(_, data []byte) error {
+			var task Task
+			if err := json.Unmarshal(data, &task); err != nil {
+				return errors.Wrap(err, "failed to unmarshal task")
+			}
+			list = append(list, task)
+			return nil
+		})
+	})
+	return list, err
+}
+
+// GetStorePath 获取定时任务数据库路径，可通过环境变量 SCHEDULE_DB_PATH 指定
+func GetStorePath() string {
+	path := os.Getenv("SCHEDULE_DB_PATH")
+	if path == "" {
+		path = "schedule.db"
+	}
+	return path
+}
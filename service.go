@@ -3,41 +3,118 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/rand"
 	"os"
 	"time"
 
 	"github.com/go-rod/rod"
 	"github.com/mattn/go-runewidth"
 	"github.com/sirupsen/logrus"
-	"github.com/xpzouying/headless_browser"
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/artifacts"
+	"github.com/xpzouying/xiaohongshu-mcp/audit"
 	"github.com/xpzouying/xiaohongshu-mcp/browser"
 	"github.com/xpzouying/xiaohongshu-mcp/configs"
 	"github.com/xpzouying/xiaohongshu-mcp/cookies"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/jobs"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/cursor"
 	"github.com/xpzouying/xiaohongshu-mcp/pkg/downloader"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/progress"
+	"github.com/xpzouying/xiaohongshu-mcp/ratelimit"
+	"github.com/xpzouying/xiaohongshu-mcp/schedule"
 	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 )
 
+// 发布任务类型，用于异步任务与定时任务
+const (
+	taskTypePublish        = "publish"
+	taskTypePublishVideo   = "publish_video"
+	taskTypeSaveDraft      = "save_draft"
+	taskTypeSaveVideoDraft = "save_video_draft"
+	taskTypePublishDraft   = "publish_draft"
+)
+
 // XiaohongshuService 小红书业务服务
-type XiaohongshuService struct{}
+type XiaohongshuService struct {
+	accounts  *accounts.Registry
+	jobs      *jobs.Manager
+	scheduler *schedule.Scheduler
+
+	feedCursors *cursor.Store[feedCursor]
+	limiter     *ratelimit.Limiter
+	auditLog    *audit.Log
+	artifacts   *artifacts.Store
+
+	onChange func(Change)
+}
+
+// Change 写操作成功后被修改的笔记或用户
+type Change struct {
+	FeedID string
+	UserID string
+}
+
+// NewXiaohongshuService 创建小红书服务实例。
+// 每次操作通过 context 中的账号选择对应账号的浏览器池，见 accounts.WithAccount。
+// 定时发布任务保存在 store 中，调用 Start 后开始调度。
+// 发布、评论、点赞等写操作执行前都会经过 limiter 的频率和每日配额检查，执行结果记录到 auditLog。
+// 发布和获取详情失败时，页面快照保存到 debugArtifacts。
+func NewXiaohongshuService(registry *accounts.Registry, store *schedule.Store, limiter *ratelimit.Limiter, auditLog *audit.Log, debugArtifacts *artifacts.Store) *XiaohongshuService {
+	s := &XiaohongshuService{
+		accounts: registry,
+		jobs:     jobs.NewManager(),
+
+		feedCursors: cursor.NewStore[feedCursor](cursor.DefaultTTL),
+		limiter:     limiter,
+		auditLog:    auditLog,
+		artifacts:   debugArtifacts,
+	}
+	cfg := configs.Get().Schedule
+	s.scheduler = schedule.NewScheduler(store, s.runScheduledTask, schedule.Config{
+		PollInterval: cfg.PollInterval.Duration,
+		RetryDelay:   cfg.RetryDelay.Duration,
+		MaxAttempts:  cfg.MaxAttempts,
+	})
+	return s
+}
+
+// Start 启动定时发布调度
+func (s *XiaohongshuService) Start() {
+	s.scheduler.Start()
+}
+
+// Close 停止定时发布调度
+func (s *XiaohongshuService) Close() {
+	s.scheduler.Stop()
+}
 
-// NewXiaohongshuService 创建小红书服务实例
-func NewXiaohongshuService() *XiaohongshuService {
-	return &XiaohongshuService{}
+// OnChange 设置写操作成功后的回调，用于通知订阅了对应笔记或用户的客户端。需在 Start 之前设置。
+func (s *XiaohongshuService) OnChange(fn func(Change)) {
+	s.onChange = fn
 }
 
 // PublishRequest 发布请求
 type PublishRequest struct {
-	Title   string   `json:"title" binding:"required"`
-	Content string   `json:"content" binding:"required"`
-	Images  []string `json:"images" binding:"required,min=1"`
-	Tags    []string `json:"tags,omitempty"`
+	Title     string     `json:"title" binding:"required"`
+	Content   string     `json:"content" binding:"required"`
+	Images    []string   `json:"images" binding:"required,min=1"`
+	Tags      []string   `json:"tags,omitempty"`
+	PublishAt *time.Time `json:"publish_at,omitempty"` // 定时发布时间，为空时立即发布
+}
+
+// auditArgs 审计日志中记录的发布参数
+func (req *PublishRequest) auditArgs() map[string]any {
+	return map[string]any{"title": req.Title, "content": req.Content, "images": req.Images, "tags": req.Tags}
 }
 
 // LoginStatusResponse 登录状态响应
 type LoginStatusResponse struct {
 	IsLoggedIn bool   `json:"is_logged_in"`
 	Username   string `json:"username,omitempty"`
+	Account    string `json:"account"`
 }
 
 // LoginQrcodeResponse 登录扫码二维码
@@ -49,57 +126,108 @@ type LoginQrcodeResponse struct {
 
 // PublishResponse 发布响应
 type PublishResponse struct {
-	Title   string `json:"title"`
-	Content string `json:"content"`
-	Images  int    `json:"images"`
-	Status  string `json:"status"`
-	PostID  string `json:"post_id,omitempty"`
+	Title     string `json:"title"`
+	Content   string `json:"content"`
+	Images    int    `json:"images"`
+	Status    string `json:"status"`
+	PostID    string `json:"post_id,omitempty"`
+	XsecToken string `json:"xsec_token,omitempty"`
+	URL       string `json:"url,omitempty"`
 }
 
 // PublishVideoRequest 发布视频请求（仅支持本地单个视频文件）
 type PublishVideoRequest struct {
-	Title   string   `json:"title" binding:"required"`
-	Content string   `json:"content" binding:"required"`
-	Video   string   `json:"video" binding:"required"`
-	Tags    []string `json:"tags,omitempty"`
+	Title     string     `json:"title" binding:"required"`
+	Content   string     `json:"content" binding:"required"`
+	Video     string     `json:"video" binding:"required"`
+	Tags      []string   `json:"tags,omitempty"`
+	PublishAt *time.Time `json:"publish_at,omitempty"` // 定时发布时间，为空时立即发布
+}
+
+// auditArgs 审计日志中记录的发布参数
+func (req *PublishVideoRequest) auditArgs() map[string]any {
+	return map[string]any{"title": req.Title, "content": req.Content, "video": req.Video, "tags": req.Tags}
 }
 
 // PublishVideoResponse 发布视频响应
 type PublishVideoResponse struct {
-	Title   string `json:"title"`
-	Content string `json:"content"`
-	Video   string `json:"video"`
-	Status  string `json:"status"`
-	PostID  string `json:"post_id,omitempty"`
+	Title     string `json:"title"`
+	Content   string `json:"content"`
+	Video     string `json:"video"`
+	Status    string `json:"status"`
+	PostID    string `json:"post_id,omitempty"`
+	XsecToken string `json:"xsec_token,omitempty"`
+	URL       string `json:"url,omitempty"`
 }
 
-// FeedsListResponse Feeds列表响应
+// FeedsListResponse Feeds列表响应，分页请求时包含页码和是否还有下一页。
+// 首页列表还会返回频道和用于继续获取的 next_cursor。
 type FeedsListResponse struct {
-	Feeds []xiaohongshu.Feed `json:"feeds"`
-	Count int                `json:"count"`
+	Feeds      []xiaohongshu.Feed `json:"feeds"`
+	Count      int                `json:"count"`
+	Channel    string             `json:"channel,omitempty"`
+	Page       int                `json:"page,omitempty"`
+	HasMore    bool               `json:"has_more,omitempty"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
+
+func newPagedFeedsResponse(result *xiaohongshu.PagedFeeds, opts xiaohongshu.PageOptions) *FeedsListResponse {
+	return &FeedsListResponse{
+		Feeds:   result.Feeds,
+		Count:   len(result.Feeds),
+		Page:    opts.Page,
+		HasMore: result.HasMore,
+	}
 }
 
-// UserProfileResponse 用户主页响应
+// UserProfileResponse 用户主页响应，指定标签页或分页时包含标签页、页码和是否还有下一页
 type UserProfileResponse struct {
 	UserBasicInfo xiaohongshu.UserBasicInfo      `json:"userBasicInfo"`
 	Interactions  []xiaohongshu.UserInteractions `json:"interactions"`
 	Feeds         []xiaohongshu.Feed             `json:"feeds"`
+	Tab           xiaohongshu.ProfileTab         `json:"tab,omitempty"`
+	Page          int                            `json:"page,omitempty"`
+	HasMore       bool                           `json:"has_more,omitempty"`
 }
 
 // DeleteCookies 删除 cookies 文件，用于登录重置
 func (s *XiaohongshuService) DeleteCookies(ctx context.Context) error {
-	cookiePath := cookies.GetCookiesFilePath()
-	cookieLoader := cookies.NewLoadCookie(cookiePath)
-	return cookieLoader.DeleteCookies()
+	account, err := s.accounts.Get(accounts.FromContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	cookieLoader := cookies.NewLoadCookie(account.CookiesPath)
+	if err := cookieLoader.DeleteCookies(); err != nil {
+		return err
+	}
+
+	// 已启动的浏览器仍持有登录态，一并重置
+	pool, err := s.accounts.Pool(account.Name)
+	if err != nil {
+		return err
+	}
+	pool.Reload()
+	return nil
+}
+
+// CookiesFilePath 返回当前账号的 cookies 文件路径
+func (s *XiaohongshuService) CookiesFilePath(ctx context.Context) string {
+	account, err := s.accounts.Get(accounts.FromContext(ctx))
+	if err != nil {
+		return ""
+	}
+	return account.CookiesPath
 }
 
 // CheckLoginStatus 检查登录状态
 func (s *XiaohongshuService) CheckLoginStatus(ctx context.Context) (*LoginStatusResponse, error) {
-	b := newBrowser()
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
+	lease, err := s.acquireLoginPage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer lease.Release()
+	page := lease.Page
 
 	loginAction := xiaohongshu.NewLogin(page)
 
@@ -111,6 +239,7 @@ func (s *XiaohongshuService) CheckLoginStatus(ctx context.Context) (*LoginStatus
 	response := &LoginStatusResponse{
 		IsLoggedIn: isLoggedIn,
 		Username:   configs.Username,
+		Account:    accounts.FromContext(ctx),
 	}
 
 	return response, nil
@@ -118,13 +247,14 @@ func (s *XiaohongshuService) CheckLoginStatus(ctx context.Context) (*LoginStatus
 
 // GetLoginQrcode 获取登录的扫码二维码
 func (s *XiaohongshuService) GetLoginQrcode(ctx context.Context) (*LoginQrcodeResponse, error) {
-	b := newBrowser()
-	page := b.NewPage()
-
-	deferFunc := func() {
-		_ = page.Close()
-		b.Close()
+	lease, err := s.acquireLoginPage(ctx)
+	if err != nil {
+		return nil, err
 	}
+	page := lease.Page
+
+	// 未登录时页面需要保留到扫码完成，由后台 goroutine 归还
+	deferFunc := lease.Release
 
 	loginAction := xiaohongshu.NewLogin(page)
 
@@ -136,18 +266,32 @@ func (s *XiaohongshuService) GetLoginQrcode(ctx context.Context) (*LoginQrcodeRe
 		return nil, err
 	}
 
-	timeout := 4 * time.Minute
+	timeout := configs.Get().Timeouts.LoginWait.Duration
 
 	if !loggedIn {
+		account, err := s.accounts.Get(accounts.FromContext(ctx))
+		if err != nil {
+			deferFunc()
+			return nil, err
+		}
+		pool, err := s.accounts.Pool(account.Name)
+		if err != nil {
+			deferFunc()
+			return nil, err
+		}
+
 		go func() {
 			ctxTimeout, cancel := context.WithTimeout(context.Background(), timeout)
 			defer cancel()
 			defer deferFunc()
 
 			if loginAction.WaitForLogin(ctxTimeout) {
-				if er := saveCookies(page); er != nil {
+				if er := saveCookies(page, account.CookiesPath); er != nil {
 					logrus.Errorf("failed to save cookies: %v", er)
+					return
 				}
+				// 其他浏览器实例仍持有旧 cookies，需要重启后生效
+				pool.Reload()
 			}
 		}()
 	}
@@ -165,15 +309,23 @@ func (s *XiaohongshuService) GetLoginQrcode(ctx context.Context) (*LoginQrcodeRe
 }
 
 // PublishContent 发布内容
-func (s *XiaohongshuService) PublishContent(ctx context.Context, req *PublishRequest) (*PublishResponse, error) {
-	// 验证标题长度
-	// 小红书限制：最大40个单位长度
-	// 中文/日文/韩文占2个单位，英文/数字占1个单位
-	if titleWidth := runewidth.StringWidth(req.Title); titleWidth > 40 {
-		return nil, fmt.Errorf("标题长度超过限制")
+func (s *XiaohongshuService) PublishContent(ctx context.Context, req *PublishRequest) (resp *PublishResponse, err error) {
+	defer func() { s.record(ctx, audit.ActionPublish, req.auditArgs(), resp, err) }()
+
+	if err := validatePublishRequest(req); err != nil {
+		return nil, err
 	}
+	if err := s.allow(ctx, ratelimit.ActionPublish); err != nil {
+		return nil, err
+	}
+
+	return s.publishImageNote(ctx, req)
+}
 
+// publishImageNote 下载图片并发布，调用前需完成参数校验和限流检查
+func (s *XiaohongshuService) publishImageNote(ctx context.Context, req *PublishRequest) (*PublishResponse, error) {
 	// 处理图片：下载URL图片或使用本地路径
+	jobs.Report(ctx, jobs.StageDownloading)
 	imagePaths, err := s.processImages(req.Images)
 	if err != nil {
 		return nil, err
@@ -188,21 +340,65 @@ func (s *XiaohongshuService) PublishContent(ctx context.Context, req *PublishReq
 	}
 
 	// 执行发布
-	if err := s.publishContent(ctx, content); err != nil {
+	result, err := s.publishContent(ctx, content)
+	if err != nil {
 		logrus.Errorf("发布内容失败: title=%s %v", content.Title, err)
 		return nil, err
 	}
 
 	response := &PublishResponse{
-		Title:   req.Title,
-		Content: req.Content,
-		Images:  len(imagePaths),
-		Status:  "发布完成",
+		Title:     req.Title,
+		Content:   req.Content,
+		Images:    len(imagePaths),
+		Status:    "发布完成",
+		PostID:    result.NoteID,
+		XsecToken: result.XsecToken,
+		URL:       result.URL,
 	}
 
 	return response, nil
 }
 
+// PublishContentAsync 校验参数后提交图文发布任务，立即返回任务信息
+func (s *XiaohongshuService) PublishContentAsync(ctx context.Context, req *PublishRequest) (*jobs.Job, error) {
+	if err := validatePublishRequest(req); err != nil {
+		s.record(ctx, audit.ActionPublish, req.auditArgs(), nil, err)
+		return nil, err
+	}
+	if err := s.allow(ctx, ratelimit.ActionPublish); err != nil {
+		s.record(ctx, audit.ActionPublish, req.auditArgs(), nil, err)
+		return nil, err
+	}
+
+	job := s.jobs.Submit(ctx, taskTypePublish, accounts.FromContext(ctx), func(ctx context.Context) (any, error) {
+		resp, err := s.publishImageNote(ctx, req)
+		s.record(ctx, audit.ActionPublish, req.auditArgs(), resp, err)
+		return resp, err
+	})
+	return &job, nil
+}
+
+// maxTitleWidth 小红书标题的最大长度单位
+const maxTitleWidth = 40
+
+// validatePublishRequest 校验图文发布参数
+func validatePublishRequest(req *PublishRequest) error {
+	// 验证标题长度
+	// 小红书限制：最大40个单位长度
+	// 中文/日文/韩文占2个单位，英文/数字占1个单位
+	if titleWidth := runewidth.StringWidth(req.Title); titleWidth > maxTitleWidth {
+		return myerrors.New(myerrors.CodeTitleTooLong, "标题长度超过限制: 当前%d，最大%d", titleWidth, maxTitleWidth)
+	}
+	return nil
+}
+
+// reportUploadProgress 返回图片上传进度回调，将进度转发给 ctx 中的进度回调
+func reportUploadProgress(ctx context.Context) func(uploaded, total int) {
+	return func(uploaded, total int) {
+		progress.Report(ctx, uploaded, total, fmt.Sprintf("已上传 %d/%d 张图片", uploaded, total))
+	}
+}
+
 // processImages 处理图片列表，支持URL下载和本地路径
 func (s *XiaohongshuService) processImages(images []string) ([]string, error) {
 	processor := downloader.NewImageProcessor()
@@ -210,37 +406,47 @@ func (s *XiaohongshuService) processImages(images []string) ([]string, error) {
 }
 
 // publishContent 执行内容发布
-func (s *XiaohongshuService) publishContent(ctx context.Context, content xiaohongshu.PublishImageContent) error {
-	b := newBrowser()
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
+func (s *XiaohongshuService) publishContent(ctx context.Context, content xiaohongshu.PublishImageContent) (*xiaohongshu.PublishResult, error) {
+	lease, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer lease.Release()
+	page := lease.Page
 
 	action, err := xiaohongshu.NewPublishImageAction(page)
 	if err != nil {
-		return err
+		return nil, s.checkChallenge(ctx, s.captureFailure(ctx, page, "publish", err))
 	}
+	action.OnStage(func(stage xiaohongshu.PublishStage) {
+		jobs.Report(ctx, jobs.Stage(stage))
+	})
+	action.OnProgress(reportUploadProgress(ctx))
 
 	// 执行发布
-	return action.Publish(ctx, content)
+	result, err := action.Publish(ctx, content)
+	if err != nil {
+		return nil, s.checkChallenge(ctx, s.captureFailure(ctx, page, "publish", err))
+	}
+	return result, nil
 }
 
 // PublishVideo 发布视频（本地文件）
-func (s *XiaohongshuService) PublishVideo(ctx context.Context, req *PublishVideoRequest) (*PublishVideoResponse, error) {
-	// 标题长度校验
-	if titleWidth := runewidth.StringWidth(req.Title); titleWidth > 40 {
-		return nil, fmt.Errorf("标题长度超过限制")
-	}
+func (s *XiaohongshuService) PublishVideo(ctx context.Context, req *PublishVideoRequest) (resp *PublishVideoResponse, err error) {
+	defer func() { s.record(ctx, audit.ActionPublish, req.auditArgs(), resp, err) }()
 
-	// 本地视频文件校验
-	if req.Video == "" {
-		return nil, fmt.Errorf("必须提供本地视频文件")
+	if err := validatePublishVideoRequest(req); err != nil {
+		return nil, err
 	}
-	if _, err := os.Stat(req.Video); err != nil {
-		return nil, fmt.Errorf("视频文件不存在或不可访问: %v", err)
+	if err := s.allow(ctx, ratelimit.ActionPublish); err != nil {
+		return nil, err
 	}
 
+	return s.publishVideoNote(ctx, req)
+}
+
+// publishVideoNote 发布视频，调用前需完成参数校验和限流检查
+func (s *XiaohongshuService) publishVideoNote(ctx context.Context, req *PublishVideoRequest) (*PublishVideoResponse, error) {
 	// 构建发布内容
 	content := xiaohongshu.PublishVideoContent{
 		Title:     req.Title,
@@ -250,83 +456,554 @@ func (s *XiaohongshuService) PublishVideo(ctx context.Context, req *PublishVideo
 	}
 
 	// 执行发布
-	if err := s.publishVideo(ctx, content); err != nil {
+	result, err := s.publishVideo(ctx, content)
+	if err != nil {
 		return nil, err
 	}
 
 	resp := &PublishVideoResponse{
-		Title:   req.Title,
-		Content: req.Content,
-		Video:   req.Video,
-		Status:  "发布完成",
+		Title:     req.Title,
+		Content:   req.Content,
+		Video:     req.Video,
+		Status:    "发布完成",
+		PostID:    result.NoteID,
+		XsecToken: result.XsecToken,
+		URL:       result.URL,
 	}
 	return resp, nil
 }
 
-// publishVideo 执行视频发布
-func (s *XiaohongshuService) publishVideo(ctx context.Context, content xiaohongshu.PublishVideoContent) error {
-	b := newBrowser()
-	defer b.Close()
+// PublishVideoAsync 校验参数后提交视频发布任务，立即返回任务信息
+func (s *XiaohongshuService) PublishVideoAsync(ctx context.Context, req *PublishVideoRequest) (*jobs.Job, error) {
+	if err := validatePublishVideoRequest(req); err != nil {
+		s.record(ctx, audit.ActionPublish, req.auditArgs(), nil, err)
+		return nil, err
+	}
+	if err := s.allow(ctx, ratelimit.ActionPublish); err != nil {
+		s.record(ctx, audit.ActionPublish, req.auditArgs(), nil, err)
+		return nil, err
+	}
+
+	job := s.jobs.Submit(ctx, taskTypePublishVideo, accounts.FromContext(ctx), func(ctx context.Context) (any, error) {
+		resp, err := s.publishVideoNote(ctx, req)
+		s.record(ctx, audit.ActionPublish, req.auditArgs(), resp, err)
+		return resp, err
+	})
+	return &job, nil
+}
+
+// validatePublishVideoRequest 校验视频发布参数
+func validatePublishVideoRequest(req *PublishVideoRequest) error {
+	// 标题长度校验
+	if titleWidth := runewidth.StringWidth(req.Title); titleWidth > maxTitleWidth {
+		return myerrors.New(myerrors.CodeTitleTooLong, "标题长度超过限制: 当前%d，最大%d", titleWidth, maxTitleWidth)
+	}
+
+	// 本地视频文件校验
+	if req.Video == "" {
+		return fmt.Errorf("必须提供本地视频文件")
+	}
+	if _, err := os.Stat(req.Video); err != nil {
+		return fmt.Errorf("视频文件不存在或不可访问: %v", err)
+	}
+	return nil
+}
 
-	page := b.NewPage()
-	defer page.Close()
+// GetJob 查询异步任务状态
+func (s *XiaohongshuService) GetJob(id string) (*jobs.Job, error) {
+	job, err := s.jobs.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// CancelJob 取消异步任务
+func (s *XiaohongshuService) CancelJob(id string) (*jobs.Job, error) {
+	job, err := s.jobs.Cancel(id)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// SchedulePublish 将图文发布加入定时队列，到 req.PublishAt 时执行
+func (s *XiaohongshuService) SchedulePublish(ctx context.Context, req *PublishRequest) (*schedule.Task, error) {
+	if err := validatePublishRequest(req); err != nil {
+		return nil, err
+	}
+	if req.PublishAt == nil {
+		return nil, fmt.Errorf("缺少定时发布时间")
+	}
+
+	payload := *req
+	payload.PublishAt = nil
+
+	task, err := s.scheduler.Add(taskTypePublish, accounts.FromContext(ctx), payload, *req.PublishAt)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// SchedulePublishVideo 将视频发布加入定时队列，到 req.PublishAt 时执行
+func (s *XiaohongshuService) SchedulePublishVideo(ctx context.Context, req *PublishVideoRequest) (*schedule.Task, error) {
+	if err := validatePublishVideoRequest(req); err != nil {
+		return nil, err
+	}
+	if req.PublishAt == nil {
+		return nil, fmt.Errorf("缺少定时发布时间")
+	}
+
+	payload := *req
+	payload.PublishAt = nil
+
+	task, err := s.scheduler.Add(taskTypePublishVideo, accounts.FromContext(ctx), payload, *req.PublishAt)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// ListScheduled 获取定时发布任务列表，status 为空时返回全部
+func (s *XiaohongshuService) ListScheduled(status schedule.Status) ([]schedule.Task, error) {
+	return s.scheduler.List(status)
+}
+
+// GetScheduled 获取定时发布任务
+func (s *XiaohongshuService) GetScheduled(id string) (*schedule.Task, error) {
+	task, err := s.scheduler.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// RescheduleTask 修改定时发布时间
+func (s *XiaohongshuService) RescheduleTask(id string, publishAt time.Time) (*schedule.Task, error) {
+	task, err := s.scheduler.Reschedule(id, publishAt)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// CancelScheduled 取消定时发布任务
+func (s *XiaohongshuService) CancelScheduled(id string) (*schedule.Task, error) {
+	task, err := s.scheduler.Cancel(id)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// runScheduledTask 执行到期的定时发布任务，使用任务记录的账号
+func (s *XiaohongshuService) runScheduledTask(ctx context.Context, task schedule.Task) (any, error) {
+	ctx = accounts.WithAccount(ctx, task.Account)
+	ctx = audit.WithSource(ctx, audit.Source{Channel: audit.ChannelSchedule, Name: task.ID})
+
+	result, err := s.runScheduledPublish(ctx, task)
+	// 已点击发布但无法确认结果时不重试，避免重复发布
+	if errors.Is(err, xiaohongshu.ErrPublishUnconfirmed) {
+		return nil, schedule.NoRetry(err)
+	}
+	return result, err
+}
+
+func (s *XiaohongshuService) runScheduledPublish(ctx context.Context, task schedule.Task) (any, error) {
+	switch task.Type {
+	case taskTypePublish:
+		var req PublishRequest
+		if err := json.Unmarshal(task.Payload, &req); err != nil {
+			return nil, fmt.Errorf("解析定时任务失败: %v", err)
+		}
+		return s.PublishContent(ctx, &req)
+	case taskTypePublishVideo:
+		var req PublishVideoRequest
+		if err := json.Unmarshal(task.Payload, &req); err != nil {
+			return nil, fmt.Errorf("解析定时任务失败: %v", err)
+		}
+		return s.PublishVideo(ctx, &req)
+	default:
+		return nil, fmt.Errorf("未知的定时任务类型: %s", task.Type)
+	}
+}
+
+// publishVideo 执行视频发布
+func (s *XiaohongshuService) publishVideo(ctx context.Context, content xiaohongshu.PublishVideoContent) (*xiaohongshu.PublishResult, error) {
+	lease, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer lease.Release()
+	page := lease.Page
 
 	action, err := xiaohongshu.NewPublishVideoAction(page)
 	if err != nil {
-		return err
+		return nil, s.checkChallenge(ctx, s.captureFailure(ctx, page, "publish_video", err))
 	}
+	action.OnStage(func(stage xiaohongshu.PublishStage) {
+		jobs.Report(ctx, jobs.Stage(stage))
+	})
+
+	result, err := action.PublishVideo(ctx, content)
+	if err != nil {
+		return nil, s.checkChallenge(ctx, s.captureFailure(ctx, page, "publish_video", err))
+	}
+	return result, nil
+}
 
-	return action.PublishVideo(ctx, content)
+// DraftSaveResponse 保存草稿响应
+type DraftSaveResponse struct {
+	Type   xiaohongshu.DraftType `json:"type"`
+	Title  string                `json:"title"`
+	Status string                `json:"status"`
 }
 
-// ListFeeds 获取Feeds列表
-func (s *XiaohongshuService) ListFeeds(ctx context.Context) (*FeedsListResponse, error) {
-	b := newBrowser()
-	defer b.Close()
+// DraftsListResponse 草稿列表响应
+type DraftsListResponse struct {
+	Drafts []xiaohongshu.Draft `json:"drafts"`
+	Count  int                 `json:"count"`
+}
 
-	page := b.NewPage()
-	defer page.Close()
+// SaveDraftAsync 提交保存图文草稿任务，内容填写完成后暂存到创作中心草稿箱，不发布
+func (s *XiaohongshuService) SaveDraftAsync(ctx context.Context, req *PublishRequest) (*jobs.Job, error) {
+	if err := validatePublishRequest(req); err != nil {
+		s.record(ctx, audit.ActionSaveDraft, req.auditArgs(), nil, err)
+		return nil, err
+	}
+	if err := validateDraftPublishAt(req.PublishAt); err != nil {
+		s.record(ctx, audit.ActionSaveDraft, req.auditArgs(), nil, err)
+		return nil, err
+	}
+	if err := s.allow(ctx, ratelimit.ActionSaveDraft); err != nil {
+		s.record(ctx, audit.ActionSaveDraft, req.auditArgs(), nil, err)
+		return nil, err
+	}
 
-	// 创建 Feeds 列表 action
-	action := xiaohongshu.NewFeedsListAction(page)
+	job := s.jobs.Submit(ctx, taskTypeSaveDraft, accounts.FromContext(ctx), func(ctx context.Context) (any, error) {
+		resp, err := s.saveImageDraft(ctx, req)
+		s.record(ctx, audit.ActionSaveDraft, req.auditArgs(), resp, err)
+		return resp, err
+	})
+	return &job, nil
+}
 
-	// 获取 Feeds 列表
-	feeds, err := action.GetFeedsList(ctx)
+// saveImageDraft 下载图片并保存草稿，调用前需完成参数校验和限流检查
+func (s *XiaohongshuService) saveImageDraft(ctx context.Context, req *PublishRequest) (*DraftSaveResponse, error) {
+	jobs.Report(ctx, jobs.StageDownloading)
+	imagePaths, err := s.processImages(req.Images)
 	if err != nil {
-		logrus.Errorf("获取 Feeds 列表失败: %v", err)
 		return nil, err
 	}
 
-	response := &FeedsListResponse{
-		Feeds: feeds,
-		Count: len(feeds),
+	err = s.withBrowserPage(ctx, func(page *rod.Page) error {
+		action, err := xiaohongshu.NewPublishImageAction(page)
+		if err != nil {
+			return s.captureFailure(ctx, page, "save_draft", err)
+		}
+		action.OnStage(func(stage xiaohongshu.PublishStage) {
+			jobs.Report(ctx, jobs.Stage(stage))
+		})
+		action.OnProgress(reportUploadProgress(ctx))
+
+		err = action.SaveDraft(ctx, xiaohongshu.PublishImageContent{
+			Title:      req.Title,
+			Content:    req.Content,
+			Tags:       req.Tags,
+			ImagePaths: imagePaths,
+		})
+		return s.captureFailure(ctx, page, "save_draft", err)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return response, nil
+	return &DraftSaveResponse{Type: xiaohongshu.DraftTypeImage, Title: req.Title, Status: "已保存草稿"}, nil
+}
+
+// SaveVideoDraftAsync 提交保存视频草稿任务
+func (s *XiaohongshuService) SaveVideoDraftAsync(ctx context.Context, req *PublishVideoRequest) (*jobs.Job, error) {
+	if err := validatePublishVideoRequest(req); err != nil {
+		s.record(ctx, audit.ActionSaveDraft, req.auditArgs(), nil, err)
+		return nil, err
+	}
+	if err := validateDraftPublishAt(req.PublishAt); err != nil {
+		s.record(ctx, audit.ActionSaveDraft, req.auditArgs(), nil, err)
+		return nil, err
+	}
+	if err := s.allow(ctx, ratelimit.ActionSaveDraft); err != nil {
+		s.record(ctx, audit.ActionSaveDraft, req.auditArgs(), nil, err)
+		return nil, err
+	}
+
+	job := s.jobs.Submit(ctx, taskTypeSaveVideoDraft, accounts.FromContext(ctx), func(ctx context.Context) (any, error) {
+		resp, err := s.saveVideoDraft(ctx, req)
+		s.record(ctx, audit.ActionSaveDraft, req.auditArgs(), resp, err)
+		return resp, err
+	})
+	return &job, nil
+}
+
+// saveVideoDraft 上传视频并保存草稿，调用前需完成参数校验和限流检查
+func (s *XiaohongshuService) saveVideoDraft(ctx context.Context, req *PublishVideoRequest) (*DraftSaveResponse, error) {
+	err := s.withBrowserPage(ctx, func(page *rod.Page) error {
+		action, err := xiaohongshu.NewPublishVideoAction(page)
+		if err != nil {
+			return s.captureFailure(ctx, page, "save_video_draft", err)
+		}
+		action.OnStage(func(stage xiaohongshu.PublishStage) {
+			jobs.Report(ctx, jobs.Stage(stage))
+		})
+
+		err = action.SaveVideoDraft(ctx, xiaohongshu.PublishVideoContent{
+			Title:     req.Title,
+			Content:   req.Content,
+			Tags:      req.Tags,
+			VideoPath: req.Video,
+		})
+		return s.captureFailure(ctx, page, "save_video_draft", err)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &DraftSaveResponse{Type: xiaohongshu.DraftTypeVideo, Title: req.Title, Status: "已保存草稿"}, nil
 }
 
-func (s *XiaohongshuService) SearchFeeds(ctx context.Context, keyword string, filters ...xiaohongshu.FilterOption) (*FeedsListResponse, error) {
-	b := newBrowser()
-	defer b.Close()
+// validateDraftPublishAt 草稿不支持定时发布，同时指定发布时间时返回错误
+func validateDraftPublishAt(publishAt *time.Time) error {
+	if publishAt != nil {
+		return errors.New("保存草稿时不能指定 publish_at，请在草稿审核后再发布")
+	}
+	return nil
+}
 
-	page := b.NewPage()
-	defer page.Close()
+// ListDrafts 获取创作中心草稿箱中的草稿
+func (s *XiaohongshuService) ListDrafts(ctx context.Context, typ xiaohongshu.DraftType) (*DraftsListResponse, error) {
+	var drafts []xiaohongshu.Draft
+	err := s.withBrowserPage(ctx, func(page *rod.Page) error {
+		action, err := xiaohongshu.NewDraftAction(page)
+		if err != nil {
+			return err
+		}
 
-	action := xiaohongshu.NewSearchAction(page)
+		drafts, err = action.List(ctx, typ)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &DraftsListResponse{Drafts: drafts, Count: len(drafts)}, nil
+}
+
+// OpenDraft 打开草稿，返回草稿中的标题和正文
+func (s *XiaohongshuService) OpenDraft(ctx context.Context, ref xiaohongshu.DraftRef) (*xiaohongshu.DraftContent, error) {
+	var content *xiaohongshu.DraftContent
+	err := s.withBrowserPage(ctx, func(page *rod.Page) error {
+		action, err := xiaohongshu.NewDraftAction(page)
+		if err != nil {
+			return err
+		}
+
+		content, err = action.Open(ctx, ref)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	feeds, err := action.Search(ctx, keyword, filters...)
+	return content, nil
+}
+
+// PublishDraftAsync 提交发布草稿任务
+func (s *XiaohongshuService) PublishDraftAsync(ctx context.Context, ref xiaohongshu.DraftRef) (*jobs.Job, error) {
+	args := map[string]any{"draft_type": ref.Type, "draft_index": ref.Index, "draft_title": ref.Title}
+	if err := s.allow(ctx, ratelimit.ActionPublish); err != nil {
+		s.record(ctx, audit.ActionPublish, args, nil, err)
+		return nil, err
+	}
+
+	job := s.jobs.Submit(ctx, taskTypePublishDraft, accounts.FromContext(ctx), func(ctx context.Context) (any, error) {
+		var result *xiaohongshu.PublishResult
+		err := s.withBrowserPage(ctx, func(page *rod.Page) error {
+			action, err := xiaohongshu.NewDraftAction(page)
+			if err != nil {
+				return s.captureFailure(ctx, page, "publish_draft", err)
+			}
+
+			jobs.Report(ctx, jobs.StageSubmitting)
+			result, err = action.Publish(ctx, ref)
+			return s.captureFailure(ctx, page, "publish_draft", err)
+		})
+		s.record(ctx, audit.ActionPublish, args, result, err)
+		if err != nil {
+			return nil, err
+		}
+
+		return result, nil
+	})
+	return &job, nil
+}
+
+// ListFeedsOptions 首页 Feeds 列表选项
+type ListFeedsOptions struct {
+	Channel string // 频道，见 xiaohongshu.FeedChannels，默认推荐
+	Limit   int    // 数量，指定后滚动加载直到凑够，不填只返回首屏
+	Cursor  string // 上一次返回的 next_cursor，用于继续获取
+}
+
+// defaultFeedsCursorLimit 首次请求未指定数量时，继续获取每次加载的数量
+const defaultFeedsCursorLimit = 20
+
+// feedCursor 首页连续翻页的状态。首页是推荐流，翻页时重新打开频道并排除已返回的内容。
+type feedCursor struct {
+	Account string
+	Channel string
+	Limit   int
+	Seen    []string
+}
+
+// ListFeeds 获取Feeds列表，返回的 next_cursor 可用于继续获取更多内容
+func (s *XiaohongshuService) ListFeeds(ctx context.Context, opts ListFeedsOptions) (*FeedsListResponse, error) {
+	account := accounts.FromContext(ctx)
+	state := feedCursor{Account: account, Channel: opts.Channel}
+
+	if opts.Cursor != "" {
+		prev, err := s.feedCursors.Get(opts.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if prev.Account != account {
+			return nil, fmt.Errorf("%w: 游标不属于当前账号", cursor.ErrInvalidCursor)
+		}
+		if opts.Channel != "" {
+			if ch, err := xiaohongshu.ParseFeedChannel(opts.Channel); err != nil || ch.Name != prev.Channel {
+				return nil, fmt.Errorf("%w: 游标属于频道 %s，与请求的频道 %s 不一致", cursor.ErrInvalidCursor, prev.Channel, opts.Channel)
+			}
+		}
+		state = prev
+		if opts.Limit == 0 {
+			opts.Limit = prev.Limit
+		}
+		// 继续获取时必须滚动加载并跳过已返回的笔记，不能退回到只读首屏
+		if opts.Limit == 0 {
+			opts.Limit = defaultFeedsCursorLimit
+		}
+	}
+
+	if opts.Limit < 0 {
+		return nil, myerrors.New(myerrors.CodeInvalidRequest, "limit 不能为负数")
+	}
+	if len(state.Seen)+opts.Limit > xiaohongshu.MaxPagedResults {
+		return nil, myerrors.New(myerrors.CodeInvalidRequest, "累计获取数量不能超过 %d", xiaohongshu.MaxPagedResults)
+	}
+
+	channel, err := xiaohongshu.ParseFeedChannel(state.Channel)
 	if err != nil {
 		return nil, err
 	}
 
+	lease, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer lease.Release()
+	page := lease.Page
+
+	var result *xiaohongshu.PagedFeeds
+	if opts.Limit == 0 {
+		// 首屏内容，保持原有行为
+		var action *xiaohongshu.FeedsListAction
+		if state.Channel == "" {
+			action, err = xiaohongshu.NewFeedsListAction(page)
+		} else {
+			action, err = xiaohongshu.NewChannelFeedsAction(page, channel)
+		}
+		if err != nil {
+			logrus.Errorf("打开 Feeds 页面失败: %v", err)
+			return nil, err
+		}
+
+		feeds, err := action.GetFeedsList(ctx)
+		if err != nil {
+			logrus.Errorf("获取 Feeds 列表失败: %v", err)
+			return nil, s.checkChallenge(ctx, err)
+		}
+		result = &xiaohongshu.PagedFeeds{Feeds: feeds, HasMore: true}
+	} else {
+		action, err := xiaohongshu.NewChannelFeedsAction(page, channel)
+		if err != nil {
+			logrus.Errorf("打开 Feeds 页面失败: %v", err)
+			return nil, err
+		}
+
+		result, err = action.GetFeedsPaged(ctx, opts.Limit, state.Seen)
+		if err != nil {
+			logrus.Errorf("获取 Feeds 列表失败: %v", err)
+			return nil, s.checkChallenge(ctx, err)
+		}
+	}
+
 	response := &FeedsListResponse{
-		Feeds: feeds,
-		Count: len(feeds),
+		Feeds:   result.Feeds,
+		Count:   len(result.Feeds),
+		Channel: channel.Name,
+		HasMore: result.HasMore,
+	}
+
+	if result.HasMore {
+		next := feedCursor{
+			Account: account,
+			Channel: channel.Name,
+			Limit:   opts.Limit,
+			Seen:    make([]string, 0, len(state.Seen)+len(result.Feeds)),
+		}
+		next.Seen = append(next.Seen, state.Seen...)
+		for _, feed := range result.Feeds {
+			next.Seen = append(next.Seen, feed.ID)
+		}
+		if len(next.Seen) < xiaohongshu.MaxPagedResults {
+			response.NextCursor = s.feedCursors.Put(next)
+		}
 	}
 
 	return response, nil
 }
 
+func (s *XiaohongshuService) SearchFeeds(ctx context.Context, keyword string, opts xiaohongshu.PageOptions, filters ...xiaohongshu.FilterOption) (*FeedsListResponse, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	lease, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer lease.Release()
+	page := lease.Page
+
+	action := xiaohongshu.NewSearchAction(page)
+
+	// 未指定 limit 时只返回首屏结果
+	if !opts.Paged() {
+		feeds, err := action.Search(ctx, keyword, filters...)
+		if err != nil {
+			return nil, s.checkChallenge(ctx, err)
+		}
+
+		return &FeedsListResponse{Feeds: feeds, Count: len(feeds)}, nil
+	}
+
+	result, err := action.SearchPaged(ctx, keyword, opts, filters...)
+	if err != nil {
+		return nil, s.checkChallenge(ctx, err)
+	}
+
+	return newPagedFeedsResponse(result, opts), nil
+}
+
 // GetFeedDetail 获取Feed详情
 func (s *XiaohongshuService) GetFeedDetail(ctx context.Context, feedID, xsecToken string, loadAllComments bool) (*FeedDetailResponse, error) {
 	return s.GetFeedDetailWithConfig(ctx, feedID, xsecToken, loadAllComments, xiaohongshu.DefaultCommentLoadConfig())
@@ -334,19 +1011,23 @@ func (s *XiaohongshuService) GetFeedDetail(ctx context.Context, feedID, xsecToke
 
 // GetFeedDetailWithConfig 使用配置获取Feed详情
 func (s *XiaohongshuService) GetFeedDetailWithConfig(ctx context.Context, feedID, xsecToken string, loadAllComments bool, config xiaohongshu.CommentLoadConfig) (*FeedDetailResponse, error) {
-	b := newBrowser()
-	defer b.Close()
-
-	page := b.NewPage()
-	defer page.Close()
+	lease, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer lease.Release()
+	page := lease.Page
 
 	// 创建 Feed 详情 action
 	action := xiaohongshu.NewFeedDetailAction(page)
+	action.OnProgress(func(loaded, target int) {
+		progress.Report(ctx, loaded, target, fmt.Sprintf("已加载 %d 条评论", loaded))
+	})
 
 	// 获取 Feed 详情
 	result, err := action.GetFeedDetailWithConfig(ctx, feedID, xsecToken, loadAllComments, config)
 	if err != nil {
-		return nil, err
+		return nil, s.checkChallenge(ctx, s.captureFailure(ctx, page, "feed_detail", err))
 	}
 
 	response := &FeedDetailResponse{
@@ -357,37 +1038,71 @@ func (s *XiaohongshuService) GetFeedDetailWithConfig(ctx context.Context, feedID
 	return response, nil
 }
 
-// UserProfile 获取用户信息
-func (s *XiaohongshuService) UserProfile(ctx context.Context, userID, xsecToken string) (*UserProfileResponse, error) {
-	b := newBrowser()
-	defer b.Close()
+// UserProfile 获取用户信息。
+// 默认返回笔记标签页首屏内容；指定 opts.Limit 时滚动加载，tab 可选择收藏、点赞标签页。
+func (s *XiaohongshuService) UserProfile(ctx context.Context, userID, xsecToken string, tab xiaohongshu.ProfileTab, opts xiaohongshu.PageOptions) (*UserProfileResponse, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
 
-	page := b.NewPage()
-	defer page.Close()
+	lease, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer lease.Release()
+	page := lease.Page
 
 	action := xiaohongshu.NewUserProfileAction(page)
 
-	result, err := action.UserProfile(ctx, userID, xsecToken)
+	if tab == xiaohongshu.ProfileTabNotes && !opts.Paged() {
+		result, err := action.UserProfile(ctx, userID, xsecToken)
+		if err != nil {
+			return nil, s.checkChallenge(ctx, err)
+		}
+		response := &UserProfileResponse{
+			UserBasicInfo: result.UserBasicInfo,
+			Interactions:  result.Interactions,
+			Feeds:         result.Feeds,
+		}
+
+		return response, nil
+	}
+
+	result, err := action.UserProfilePaged(ctx, userID, xsecToken, tab, opts)
 	if err != nil {
-		return nil, err
+		return nil, s.checkChallenge(ctx, err)
 	}
+
 	response := &UserProfileResponse{
 		UserBasicInfo: result.UserBasicInfo,
 		Interactions:  result.Interactions,
 		Feeds:         result.Feeds,
+		Tab:           result.Tab,
+		HasMore:       result.HasMore,
+	}
+	if opts.Paged() {
+		response.Page = opts.Page
 	}
 
 	return response, nil
-
 }
 
 // PostCommentToFeed 发表评论到Feed
-func (s *XiaohongshuService) PostCommentToFeed(ctx context.Context, feedID, xsecToken, content string) (*PostCommentResponse, error) {
-	b := newBrowser()
-	defer b.Close()
+func (s *XiaohongshuService) PostCommentToFeed(ctx context.Context, feedID, xsecToken, content string) (resp *PostCommentResponse, err error) {
+	defer func() {
+		s.record(ctx, audit.ActionComment, map[string]any{"feed_id": feedID, "content": content}, resp, err)
+	}()
+
+	if err := s.allow(ctx, ratelimit.ActionComment); err != nil {
+		return nil, err
+	}
 
-	page := b.NewPage()
-	defer page.Close()
+	lease, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer lease.Release()
+	page := lease.Page
 
 	action := xiaohongshu.NewCommentFeedAction(page)
 
@@ -399,12 +1114,19 @@ func (s *XiaohongshuService) PostCommentToFeed(ctx context.Context, feedID, xsec
 }
 
 // LikeFeed 点赞笔记
-func (s *XiaohongshuService) LikeFeed(ctx context.Context, feedID, xsecToken string) (*ActionResult, error) {
-	b := newBrowser()
-	defer b.Close()
+func (s *XiaohongshuService) LikeFeed(ctx context.Context, feedID, xsecToken string) (resp *ActionResult, err error) {
+	defer func() { s.record(ctx, audit.ActionLike, map[string]any{"feed_id": feedID}, resp, err) }()
+
+	if err := s.allow(ctx, ratelimit.ActionLike); err != nil {
+		return nil, err
+	}
 
-	page := b.NewPage()
-	defer page.Close()
+	lease, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer lease.Release()
+	page := lease.Page
 
 	action := xiaohongshu.NewLikeAction(page)
 	if err := action.Like(ctx, feedID, xsecToken); err != nil {
@@ -414,12 +1136,19 @@ func (s *XiaohongshuService) LikeFeed(ctx context.Context, feedID, xsecToken str
 }
 
 // UnlikeFeed 取消点赞笔记
-func (s *XiaohongshuService) UnlikeFeed(ctx context.Context, feedID, xsecToken string) (*ActionResult, error) {
-	b := newBrowser()
-	defer b.Close()
+func (s *XiaohongshuService) UnlikeFeed(ctx context.Context, feedID, xsecToken string) (resp *ActionResult, err error) {
+	defer func() { s.record(ctx, audit.ActionUnlike, map[string]any{"feed_id": feedID}, resp, err) }()
+
+	if err := s.allow(ctx, ratelimit.ActionLike); err != nil {
+		return nil, err
+	}
 
-	page := b.NewPage()
-	defer page.Close()
+	lease, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer lease.Release()
+	page := lease.Page
 
 	action := xiaohongshu.NewLikeAction(page)
 	if err := action.Unlike(ctx, feedID, xsecToken); err != nil {
@@ -429,12 +1158,19 @@ func (s *XiaohongshuService) UnlikeFeed(ctx context.Context, feedID, xsecToken s
 }
 
 // FavoriteFeed 收藏笔记
-func (s *XiaohongshuService) FavoriteFeed(ctx context.Context, feedID, xsecToken string) (*ActionResult, error) {
-	b := newBrowser()
-	defer b.Close()
+func (s *XiaohongshuService) FavoriteFeed(ctx context.Context, feedID, xsecToken string) (resp *ActionResult, err error) {
+	defer func() { s.record(ctx, audit.ActionFavorite, map[string]any{"feed_id": feedID}, resp, err) }()
 
-	page := b.NewPage()
-	defer page.Close()
+	if err := s.allow(ctx, ratelimit.ActionFavorite); err != nil {
+		return nil, err
+	}
+
+	lease, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer lease.Release()
+	page := lease.Page
 
 	action := xiaohongshu.NewFavoriteAction(page)
 	if err := action.Favorite(ctx, feedID, xsecToken); err != nil {
@@ -444,12 +1180,19 @@ func (s *XiaohongshuService) FavoriteFeed(ctx context.Context, feedID, xsecToken
 }
 
 // UnfavoriteFeed 取消收藏笔记
-func (s *XiaohongshuService) UnfavoriteFeed(ctx context.Context, feedID, xsecToken string) (*ActionResult, error) {
-	b := newBrowser()
-	defer b.Close()
+func (s *XiaohongshuService) UnfavoriteFeed(ctx context.Context, feedID, xsecToken string) (resp *ActionResult, err error) {
+	defer func() { s.record(ctx, audit.ActionUnfavorite, map[string]any{"feed_id": feedID}, resp, err) }()
 
-	page := b.NewPage()
-	defer page.Close()
+	if err := s.allow(ctx, ratelimit.ActionFavorite); err != nil {
+		return nil, err
+	}
+
+	lease, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer lease.Release()
+	page := lease.Page
 
 	action := xiaohongshu.NewFavoriteAction(page)
 	if err := action.Unfavorite(ctx, feedID, xsecToken); err != nil {
@@ -458,13 +1201,311 @@ func (s *XiaohongshuService) UnfavoriteFeed(ctx context.Context, feedID, xsecTok
 	return &ActionResult{FeedID: feedID, Success: true, Message: "取消收藏成功或未收藏"}, nil
 }
 
+// FollowUser 关注用户
+func (s *XiaohongshuService) FollowUser(ctx context.Context, userID, xsecToken string) (resp *xiaohongshu.FollowResult, err error) {
+	defer func() { s.record(ctx, audit.ActionFollow, map[string]any{"user_id": userID}, resp, err) }()
+
+	if err := s.allow(ctx, ratelimit.ActionFollow); err != nil {
+		return nil, err
+	}
+
+	lease, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer lease.Release()
+	page := lease.Page
+
+	action := xiaohongshu.NewFollowAction(page)
+	return action.Follow(ctx, userID, xsecToken)
+}
+
+// UnfollowUser 取消关注用户
+func (s *XiaohongshuService) UnfollowUser(ctx context.Context, userID, xsecToken string) (resp *xiaohongshu.FollowResult, err error) {
+	defer func() { s.record(ctx, audit.ActionUnfollow, map[string]any{"user_id": userID}, resp, err) }()
+
+	if err := s.allow(ctx, ratelimit.ActionFollow); err != nil {
+		return nil, err
+	}
+
+	lease, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer lease.Release()
+	page := lease.Page
+
+	action := xiaohongshu.NewFollowAction(page)
+	return action.Unfollow(ctx, userID, xsecToken)
+}
+
+// 批量互动的操作类型
+const (
+	batchActionLike       = "like"
+	batchActionUnlike     = "unlike"
+	batchActionFavorite   = "favorite"
+	batchActionUnfavorite = "unfavorite"
+	batchActionComment    = "comment"
+	batchActionFollow     = "follow"
+	batchActionUnfollow   = "unfollow"
+)
+
+// 批量互动中单项操作的状态
+const (
+	batchStatusSucceeded = "succeeded"
+	batchStatusFailed    = "failed"
+	batchStatusSkipped   = "skipped" // 请求被取消，未执行
+)
+
+const (
+	// MaxBatchOperations 单次批量互动最多的操作数
+	MaxBatchOperations = 50
+)
+
+// BatchOperation 批量互动中的一项操作。参数在执行时逐项校验，不合法的操作单独标记为失败。
+type BatchOperation struct {
+	Action    string `json:"action"`            // like|unlike|favorite|unfavorite|comment|follow|unfollow
+	FeedID    string `json:"feed_id,omitempty"` // 笔记操作必填
+	UserID    string `json:"user_id,omitempty"` // 关注操作必填
+	XsecToken string `json:"xsec_token"`
+	Content   string `json:"content,omitempty"` // 评论内容，comment 必填
+}
+
+// validate 校验操作参数
+func (op BatchOperation) validate() error {
+	switch op.Action {
+	case batchActionLike, batchActionUnlike, batchActionFavorite, batchActionUnfavorite:
+		if op.FeedID == "" {
+			return fmt.Errorf("%s 操作缺少 feed_id", op.Action)
+		}
+	case batchActionComment:
+		if op.FeedID == "" {
+			return fmt.Errorf("comment 操作缺少 feed_id")
+		}
+		if op.Content == "" {
+			return fmt.Errorf("comment 操作缺少 content")
+		}
+	case batchActionFollow, batchActionUnfollow:
+		if op.UserID == "" {
+			return fmt.Errorf("%s 操作缺少 user_id", op.Action)
+		}
+	default:
+		return fmt.Errorf("未知的操作: %s，可选 like|unlike|favorite|unfavorite|comment|follow|unfollow", op.Action)
+	}
+	if op.XsecToken == "" {
+		return fmt.Errorf("缺少 xsec_token")
+	}
+	return nil
+}
+
+// limitAction 操作对应的限流类型
+func (op BatchOperation) limitAction() ratelimit.Action {
+	switch op.Action {
+	case batchActionLike, batchActionUnlike:
+		return ratelimit.ActionLike
+	case batchActionFavorite, batchActionUnfavorite:
+		return ratelimit.ActionFavorite
+	case batchActionComment:
+		return ratelimit.ActionComment
+	default:
+		return ratelimit.ActionFollow
+	}
+}
+
+// auditArgs 审计日志中记录的操作参数，不含 xsec_token
+func (op BatchOperation) auditArgs() map[string]any {
+	args := map[string]any{}
+	if op.FeedID != "" {
+		args["feed_id"] = op.FeedID
+	}
+	if op.UserID != "" {
+		args["user_id"] = op.UserID
+	}
+	if op.Content != "" {
+		args["content"] = op.Content
+	}
+	return args
+}
+
+// BatchOperationResult 单项操作的结果
+type BatchOperationResult struct {
+	Index      int    `json:"index"`
+	Action     string `json:"action"`
+	FeedID     string `json:"feed_id,omitempty"`
+	UserID     string `json:"user_id,omitempty"`
+	Status     string `json:"status"` // succeeded|failed|skipped
+	Message    string `json:"message,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Code       string `json:"code,omitempty"`        // 失败时的错误代码
+	RetryAfter int    `json:"retry_after,omitempty"` // 被限流时建议的重试等待秒数
+}
+
+// BatchInteractResponse 批量互动响应
+type BatchInteractResponse struct {
+	Results   []BatchOperationResult `json:"results"`
+	Succeeded int                    `json:"succeeded"`
+	Failed    int                    `json:"failed"`
+	Skipped   int                    `json:"skipped"`
+}
+
+// BatchInteract 在同一个浏览器页面上依次执行多项互动操作，操作之间随机间隔。
+// 每项操作单独返回结果，某一项失败不影响其他操作。
+func (s *XiaohongshuService) BatchInteract(ctx context.Context, ops []BatchOperation) (*BatchInteractResponse, error) {
+	if len(ops) == 0 {
+		return nil, fmt.Errorf("operations 不能为空")
+	}
+	if len(ops) > MaxBatchOperations {
+		return nil, fmt.Errorf("单次最多 %d 项操作", MaxBatchOperations)
+	}
+
+	lease, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer lease.Release()
+	page := lease.Page
+
+	response := &BatchInteractResponse{Results: make([]BatchOperationResult, 0, len(ops))}
+	executed := false
+	for i, op := range ops {
+		result := BatchOperationResult{Index: i, Action: op.Action, FeedID: op.FeedID, UserID: op.UserID}
+
+		if err := op.validate(); err != nil {
+			result.Status = batchStatusFailed
+			result.Error = err.Error()
+			response.add(result)
+			continue
+		}
+
+		if err := s.allow(ctx, op.limitAction()); err != nil {
+			var limitErr *ratelimit.LimitError
+			if errors.As(err, &limitErr) {
+				result.Code = string(myerrors.CodeRateLimited)
+				result.RetryAfter = limitErr.RetryAfterSeconds()
+			}
+			result.Status = batchStatusFailed
+			result.Error = err.Error()
+			response.add(result)
+			s.record(ctx, audit.Action(op.Action), op.auditArgs(), nil, err)
+			continue
+		}
+
+		if executed {
+			// 两次操作之间的随机间隔，模拟真人操作节奏
+			sleepContext(ctx, randomDelay(configs.Get().Delays.BatchInterval))
+		}
+		if ctx.Err() != nil {
+			result.Status = batchStatusSkipped
+			result.Error = ctx.Err().Error()
+			response.add(result)
+			continue
+		}
+		executed = true
+
+		message, err := runBatchOperation(ctx, page, op)
+		s.record(ctx, audit.Action(op.Action), op.auditArgs(), message, err)
+		if err != nil {
+			logrus.Warnf("批量互动第 %d 项失败: action=%s, %v", i, op.Action, err)
+			result.Status = batchStatusFailed
+			result.Error = err.Error()
+			result.Code = string(myerrors.CodeOf(err))
+		} else {
+			result.Status = batchStatusSucceeded
+			result.Message = message
+		}
+		response.add(result)
+	}
+
+	logrus.Infof("批量互动完成: 成功 %d，失败 %d，跳过 %d", response.Succeeded, response.Failed, response.Skipped)
+	return response, nil
+}
+
+func (r *BatchInteractResponse) add(result BatchOperationResult) {
+	switch result.Status {
+	case batchStatusSucceeded:
+		r.Succeeded++
+	case batchStatusSkipped:
+		r.Skipped++
+	default:
+		r.Failed++
+	}
+	r.Results = append(r.Results, result)
+}
+
+// runBatchOperation 在给定页面上执行一项操作，页面操作中的 panic 只影响当前项
+func runBatchOperation(ctx context.Context, page *rod.Page, op BatchOperation) (message string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("操作执行失败: %v", r)
+		}
+	}()
+
+	switch op.Action {
+	case batchActionLike:
+		return "点赞成功或已点赞", xiaohongshu.NewLikeAction(page).Like(ctx, op.FeedID, op.XsecToken)
+	case batchActionUnlike:
+		return "取消点赞成功或未点赞", xiaohongshu.NewLikeAction(page).Unlike(ctx, op.FeedID, op.XsecToken)
+	case batchActionFavorite:
+		return "收藏成功或已收藏", xiaohongshu.NewFavoriteAction(page).Favorite(ctx, op.FeedID, op.XsecToken)
+	case batchActionUnfavorite:
+		return "取消收藏成功或未收藏", xiaohongshu.NewFavoriteAction(page).Unfavorite(ctx, op.FeedID, op.XsecToken)
+	case batchActionComment:
+		return "评论发表成功", xiaohongshu.NewCommentFeedAction(page).PostComment(ctx, op.FeedID, op.XsecToken, op.Content)
+	case batchActionFollow, batchActionUnfollow:
+		action := xiaohongshu.NewFollowAction(page)
+		follow := action.Follow
+		if op.Action == batchActionUnfollow {
+			follow = action.Unfollow
+		}
+		result, err := follow(ctx, op.UserID, op.XsecToken)
+		if err != nil {
+			return "", err
+		}
+		if !result.Changed {
+			return "关注状态未变化，已是目标状态", nil
+		}
+		return "关注状态已更新", nil
+	}
+
+	return "", fmt.Errorf("未知的操作: %s", op.Action)
+}
+
+// randomDelay 返回延迟范围内的随机时长
+func randomDelay(r configs.DelayRange) time.Duration {
+	if r.Max.Duration <= r.Min.Duration {
+		return r.Min.Duration
+	}
+	return r.Min.Duration + time.Duration(rand.Int63n(int64(r.Max.Duration-r.Min.Duration)))
+}
+
+// sleepContext 等待指定时间，context 取消时提前返回
+func sleepContext(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
 // ReplyCommentToFeed 回复指定评论
-func (s *XiaohongshuService) ReplyCommentToFeed(ctx context.Context, feedID, xsecToken, commentID, userID, content string) (*ReplyCommentResponse, error) {
-	b := newBrowser()
-	defer b.Close()
+func (s *XiaohongshuService) ReplyCommentToFeed(ctx context.Context, feedID, xsecToken, commentID, userID, content string) (resp *ReplyCommentResponse, err error) {
+	defer func() {
+		args := map[string]any{"feed_id": feedID, "comment_id": commentID, "user_id": userID, "content": content}
+		s.record(ctx, audit.ActionReply, args, resp, err)
+	}()
 
-	page := b.NewPage()
-	defer page.Close()
+	if err := s.allow(ctx, ratelimit.ActionReply); err != nil {
+		return nil, err
+	}
+
+	lease, err := s.acquirePage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer lease.Release()
+	page := lease.Page
 
 	action := xiaohongshu.NewCommentFeedAction(page)
 
@@ -481,11 +1522,7 @@ func (s *XiaohongshuService) ReplyCommentToFeed(ctx context.Context, feedID, xse
 	}, nil
 }
 
-func newBrowser() *headless_browser.Browser {
-	return browser.NewBrowser(configs.IsHeadless(), browser.WithBinPath(configs.GetBinPath()))
-}
-
-func saveCookies(page *rod.Page) error {
+func saveCookies(page *rod.Page, cookiesPath string) error {
 	cks, err := page.Browser().GetCookies()
 	if err != nil {
 		return err
@@ -496,19 +1533,179 @@ func saveCookies(page *rod.Page) error {
 		return err
 	}
 
-	cookieLoader := cookies.NewLoadCookie(cookies.GetCookiesFilePath())
+	cookieLoader := cookies.NewLoadCookie(cookiesPath)
 	return cookieLoader.SaveCookies(data)
 }
 
-// withBrowserPage 执行需要浏览器页面的操作的通用函数
-func withBrowserPage(fn func(*rod.Page) error) error {
-	b := newBrowser()
-	defer b.Close()
+// acquirePage 从当前账号的浏览器池租用页面。账号因验证码暂停时直接返回错误，避免继续触发风控。
+func (s *XiaohongshuService) acquirePage(ctx context.Context) (*browser.Lease, error) {
+	name := accounts.FromContext(ctx)
+	if challenge, paused := s.accounts.Challenge(name); paused {
+		return nil, myerrors.New(myerrors.CodeCaptchaRequired,
+			"账号 %s 于 %s 遇到验证码（%s），已暂停使用，请人工完成验证后恢复账号",
+			name, challenge.DetectedAt.Format(time.DateTime), challenge.Reason)
+	}
+	return s.acquireLoginPage(ctx)
+}
+
+// acquireLoginPage 租用页面，不检查验证码暂停状态，供登录相关操作使用
+func (s *XiaohongshuService) acquireLoginPage(ctx context.Context) (*browser.Lease, error) {
+	pool, err := s.accounts.Pool(accounts.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return pool.Acquire(ctx)
+}
+
+// checkChallenge 操作遇到验证码时暂停当前账号，等待人工处理，返回原错误
+func (s *XiaohongshuService) checkChallenge(ctx context.Context, err error) error {
+	var captchaErr *xiaohongshu.CaptchaError
+	if !errors.As(err, &captchaErr) {
+		return err
+	}
+
+	name := accounts.FromContext(ctx)
+	if pauseErr := s.accounts.Pause(name, accounts.Challenge{
+		Reason:     captchaErr.Reason,
+		URL:        captchaErr.URL,
+		Screenshot: captchaErr.Screenshot,
+	}); pauseErr != nil {
+		logrus.Errorf("暂停账号 %s 失败: %v", name, pauseErr)
+	}
+	return err
+}
+
+// captureFailure 操作失败时保存页面截图、HTML 和地址，返回附带快照ID的错误。
+// err 为空、未配置快照目录或操作被取消时原样返回。
+func (s *XiaohongshuService) captureFailure(ctx context.Context, page *rod.Page, action string, err error) error {
+	if err == nil || s.artifacts == nil || errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	// 操作使用的 context 可能已超时，快照使用独立的超时
+	snapshot := xiaohongshu.CaptureSnapshot(page.Timeout(configs.Get().Timeouts.Snapshot.Duration))
+
+	id, saveErr := s.artifacts.Save(artifacts.Meta{
+		Account: accounts.FromContext(ctx),
+		Action:  action,
+		URL:     snapshot.URL,
+		Title:   snapshot.Title,
+		Error:   err.Error(),
+	}, map[string][]byte{
+		artifacts.FileScreenshot: snapshot.Screenshot,
+		artifacts.FileHTML:       []byte(snapshot.HTML),
+	})
+	if saveErr != nil {
+		logrus.Errorf("保存调试快照失败: %v", saveErr)
+		return err
+	}
+
+	logrus.Infof("%s 失败，已保存调试快照: %s, url=%s", action, id, snapshot.URL)
+	return &artifacts.Error{ID: id, Err: err}
+}
+
+// GetArtifact 获取调试快照信息
+func (s *XiaohongshuService) GetArtifact(id string) (*artifacts.Meta, error) {
+	if s.artifacts == nil {
+		return nil, artifacts.ErrNotFound
+	}
+	return s.artifacts.Get(id)
+}
+
+// ArtifactFilePath 获取调试快照中文件的路径
+func (s *XiaohongshuService) ArtifactFilePath(id, name string) (string, error) {
+	if s.artifacts == nil {
+		return "", artifacts.ErrNotFound
+	}
+	return s.artifacts.FilePath(id, name)
+}
+
+// GetChallenge 获取当前账号待处理的验证码
+func (s *XiaohongshuService) GetChallenge(ctx context.Context) (*AccountChallengeResponse, error) {
+	name := accounts.FromContext(ctx)
+	challenge, paused := s.accounts.Challenge(name)
+	if !paused {
+		return nil, fmt.Errorf("%w: %s", accounts.ErrNoChallenge, name)
+	}
+
+	return &AccountChallengeResponse{
+		Account:    name,
+		Reason:     challenge.Reason,
+		URL:        challenge.URL,
+		DetectedAt: challenge.DetectedAt,
+		Screenshot: challenge.Screenshot,
+	}, nil
+}
+
+// ResumeAccount 人工完成验证后恢复当前账号
+func (s *XiaohongshuService) ResumeAccount(ctx context.Context) error {
+	return s.accounts.Resume(accounts.FromContext(ctx))
+}
+
+// allow 对当前账号的写操作做频率和每日配额检查
+func (s *XiaohongshuService) allow(ctx context.Context, action ratelimit.Action) error {
+	if s.limiter == nil {
+		return nil
+	}
+	return s.limiter.Allow(accounts.FromContext(ctx), action)
+}
+
+// record 记录写操作的审计日志，操作成功时通知 onChange。写入失败只打印日志，不影响操作结果。
+func (s *XiaohongshuService) record(ctx context.Context, action audit.Action, args map[string]any, result any, err error) {
+	if err == nil && s.onChange != nil {
+		feedID, _ := args["feed_id"].(string)
+		userID, _ := args["user_id"].(string)
+		if feedID != "" || userID != "" {
+			s.onChange(Change{FeedID: feedID, UserID: userID})
+		}
+	}
+
+	if s.auditLog == nil {
+		return
+	}
+
+	source := audit.SourceFrom(ctx)
+	entry := audit.Entry{
+		Account: accounts.FromContext(ctx),
+		Action:  action,
+		Channel: source.Channel,
+		Source:  source.Name,
+		Client:  source.Client,
+		Remote:  source.Remote,
+		JobID:   jobs.IDFromContext(ctx),
+		Args:    args,
+		Outcome: audit.OutcomeSuccess,
+		Result:  result,
+	}
+	if err != nil {
+		entry.Outcome = audit.OutcomeFailed
+		entry.Error = err.Error()
+		entry.Result = nil
+	}
+
+	if err := s.auditLog.Record(entry); err != nil {
+		logrus.Errorf("写入审计日志失败: action=%s, %v", action, err)
+	}
+}
+
+// QueryAudit 查询写操作审计日志
+func (s *XiaohongshuService) QueryAudit(filter audit.Filter) ([]audit.Entry, error) {
+	if s.auditLog == nil {
+		return nil, fmt.Errorf("审计日志未启用")
+	}
+	return s.auditLog.Query(filter)
+}
 
-	page := b.NewPage()
-	defer page.Close()
+// withBrowserPage 从浏览器池租用页面执行操作的通用函数
+func (s *XiaohongshuService) withBrowserPage(ctx context.Context, fn func(*rod.Page) error) error {
+	lease, err := s.acquirePage(ctx)
+	if err != nil {
+		return err
+	}
+	defer lease.Release()
+	page := lease.Page
 
-	return fn(page)
+	return s.checkChallenge(ctx, fn(page))
 }
 
 // GetMyProfile 获取当前登录用户的个人信息
@@ -516,7 +1713,7 @@ func (s *XiaohongshuService) GetMyProfile(ctx context.Context) (*UserProfileResp
 	var result *xiaohongshu.UserProfileResponse
 	var err error
 
-	err = withBrowserPage(func(page *rod.Page) error {
+	err = s.withBrowserPage(ctx, func(page *rod.Page) error {
 		action := xiaohongshu.NewUserProfileAction(page)
 		result, err = action.GetMyProfileViaSidebar(ctx)
 		return err
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/sirupsen/logrus"
+)
+
+// ioTransport 在指定的输入输出上传输换行分隔的 MCP 消息。
+// SDK 的 StdioTransport 在连接时才读取 os.Stdout，stdio 模式下 os.Stdout 已改指向标准错误，
+// 因此由 main 在启动时保存原始的标准输入输出并显式传入。
+type ioTransport struct {
+	in  io.ReadCloser
+	out io.WriteCloser
+}
+
+func (t *ioTransport) Connect(context.Context) (mcp.Connection, error) {
+	c := &ioConn{
+		out:      t.out,
+		closer:   t.in,
+		incoming: make(chan readResult),
+		closed:   make(chan struct{}),
+	}
+	go c.readLoop(t.in)
+	return c, nil
+}
+
+// parseErrorResponse 无法解析的消息没有可用的请求 ID，按 JSON-RPC 规范以 id 为 null 回复解析错误
+var parseErrorResponse = []byte(`{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"Parse error"}}`)
+
+type readResult struct {
+	msg jsonrpc.Message
+	err error
+}
+
+// ioConn 在单独的 goroutine 中读取消息，Close 时即使输入仍阻塞也能让 Read 返回
+type ioConn struct {
+	out    io.WriteCloser
+	closer io.Closer
+
+	writeMu  sync.Mutex
+	incoming chan readResult
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func (c *ioConn) readLoop(in io.Reader) {
+	r := bufio.NewReader(in)
+	for {
+		line, err := r.ReadBytes('\n')
+
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			msg, decodeErr := jsonrpc.DecodeMessage(line)
+			if decodeErr != nil {
+				// 单条格式错误的消息不结束会话，回复解析错误后继续读取
+				logrus.Warnf("忽略无法解析的 MCP 消息: %v, 内容: %.200s", decodeErr, line)
+				_ = c.writeRaw(parseErrorResponse)
+			} else if !c.deliver(readResult{msg: msg}) {
+				return
+			}
+		}
+
+		if err != nil {
+			c.deliver(readResult{err: err})
+			return
+		}
+	}
+}
+
+// deliver 将读取结果交给 Read，连接已关闭时返回 false
+func (c *ioConn) deliver(res readResult) bool {
+	select {
+	case c.incoming <- res:
+		return true
+	case <-c.closed:
+		return false
+	}
+}
+
+func (c *ioConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.closed:
+		return nil, io.EOF
+	case res := <-c.incoming:
+		return res.msg, res.err
+	}
+}
+
+func (c *ioConn) Write(_ context.Context, msg jsonrpc.Message) error {
+	data, err := jsonrpc.EncodeMessage(msg)
+	if err != nil {
+		return err
+	}
+	return c.writeRaw(data)
+}
+
+func (c *ioConn) writeRaw(data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err := c.out.Write(append(data, '\n'))
+	return err
+}
+
+func (c *ioConn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		err = errors.Join(c.closer.Close(), c.out.Close())
+	})
+	return err
+}
+
+func (c *ioConn) SessionID() string { return "" }
@@ -1,14 +1,24 @@
 package main
 
-import "github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+import (
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/accounts"
+	"github.com/xpzouying/xiaohongshu-mcp/audit"
+	"github.com/xpzouying/xiaohongshu-mcp/jobs"
+	"github.com/xpzouying/xiaohongshu-mcp/ratelimit"
+	"github.com/xpzouying/xiaohongshu-mcp/schedule"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
 
 // HTTP API 响应类型
 
 // ErrorResponse 错误响应
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Code    string `json:"code"`
-	Details any    `json:"details,omitempty"`
+	Error      string `json:"error"`
+	Code       string `json:"code"`
+	Details    any    `json:"details,omitempty"`
+	ArtifactID string `json:"artifact_id,omitempty"` // 失败时保存的调试快照ID，见 /api/v1/debug/artifacts/{id}
 }
 
 // SuccessResponse 成功响应
@@ -24,6 +34,11 @@ type SuccessResponse struct {
 type MCPToolResult struct {
 	Content []MCPContent `json:"content"`
 	IsError bool         `json:"isError,omitempty"`
+	Code    string       `json:"code,omitempty"` // 出错时的错误代码，与 HTTP API 的 ErrorResponse.Code 一致
+	// 出错时保存的调试快照ID，与 HTTP API 的 ErrorResponse.ArtifactID 一致
+	ArtifactID string `json:"artifact_id,omitempty"`
+	// 结构化结果，作为 structuredContent 返回，需符合工具声明的 OutputSchema；出错时为空
+	Structured any `json:"-"`
 }
 
 // MCPContent MCP 内容（内部使用）
@@ -57,12 +72,14 @@ type FeedDetailRequest struct {
 type SearchFeedsRequest struct {
 	Keyword string                   `json:"keyword" binding:"required"`
 	Filters xiaohongshu.FilterOption `json:"filters,omitempty"`
+	Limit   int                      `json:"limit,omitempty"` // 每页数量，不填只返回首屏结果
+	Page    int                      `json:"page,omitempty"`  // 页码，从 1 开始
 }
 
 // FeedDetailResponse Feed详情响应
 type FeedDetailResponse struct {
-	FeedID string `json:"feed_id"`
-	Data   any    `json:"data"`
+	FeedID string                          `json:"feed_id"`
+	Data   *xiaohongshu.FeedDetailResponse `json:"data"`
 }
 
 // PostCommentRequest 发表评论请求
@@ -101,6 +118,58 @@ type ReplyCommentResponse struct {
 type UserProfileRequest struct {
 	UserID    string `json:"user_id" binding:"required"`
 	XsecToken string `json:"xsec_token" binding:"required"`
+	Tab       string `json:"tab,omitempty"`   // notes|collected|liked，默认 notes
+	Limit     int    `json:"limit,omitempty"` // 每页数量，不填只返回首屏笔记
+	Page      int    `json:"page,omitempty"`  // 页码，从 1 开始
+}
+
+// RateLimitDetails 限流错误详情
+type RateLimitDetails struct {
+	Account    string           `json:"account"`
+	Action     ratelimit.Action `json:"action"`
+	Reason     string           `json:"reason"`      // rate: 超过频率限制，daily_quota: 超过每日配额
+	RetryAfter int              `json:"retry_after"` // 建议的重试等待秒数
+	RetryAt    time.Time        `json:"retry_at"`
+	Message    string           `json:"message"`
+}
+
+// FollowUserRequest 关注/取消关注请求
+type FollowUserRequest struct {
+	UserID    string `json:"user_id" binding:"required"`
+	XsecToken string `json:"xsec_token" binding:"required"`
+}
+
+// FeedActionRequest 点赞/收藏等笔记互动请求
+type FeedActionRequest struct {
+	FeedID    string `json:"feed_id" binding:"required"`
+	XsecToken string `json:"xsec_token" binding:"required"`
+}
+
+// BatchFeedActionRequest 批量点赞/收藏请求
+type BatchFeedActionRequest struct {
+	Feeds []FeedActionRequest `json:"feeds" binding:"required,min=1,max=50,dive"`
+}
+
+// BatchInteractRequest 批量互动请求
+type BatchInteractRequest struct {
+	Operations []BatchOperation `json:"operations" binding:"required,min=1,max=50"`
+}
+
+// BatchFeedActionResponse 批量点赞/收藏响应，每条笔记单独返回结果
+type BatchFeedActionResponse struct {
+	Results   []BatchFeedActionResult `json:"results"`
+	Succeeded int                     `json:"succeeded"`
+	Failed    int                     `json:"failed"`
+}
+
+// BatchFeedActionResult 批量操作中单条笔记的结果
+type BatchFeedActionResult struct {
+	FeedID     string `json:"feed_id"`
+	Success    bool   `json:"success"`
+	Message    string `json:"message,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Code       string `json:"code,omitempty"`        // 失败时的错误代码
+	RetryAfter int    `json:"retry_after,omitempty"` // 被限流时建议的重试等待秒数
 }
 
 // ActionResult 通用动作响应（点赞/收藏等）
@@ -122,7 +191,68 @@ type PublishContentRequest struct {
 type PublishContentResponse struct {
 	Success bool   `json:"success" example:"true"`
 	PostID  string `json:"post_id,omitempty" example:"post_12345"`
+	URL     string `json:"url,omitempty" example:"https://www.xiaohongshu.com/explore/post_12345"`
 	Title   string `json:"title" example:"我的第一篇小红书"`
 	Status  string `json:"status" example:"published"`
 	Message string `json:"message" example:"文章发布成功"`
 }
+
+// PublishSubmitResponse 发布提交结果。
+// 立即发布和保存草稿返回异步任务 Job，定时发布返回定时任务 Task。
+type PublishSubmitResponse struct {
+	Job  *jobs.Job      `json:"job,omitempty"`
+	Task *schedule.Task `json:"task,omitempty"`
+}
+
+// DeleteCookiesResponse 删除 cookies 响应
+type DeleteCookiesResponse struct {
+	Account     string `json:"account"`
+	CookiesPath string `json:"cookies_path"`
+}
+
+// ResumeAccountResponse 恢复账号响应
+type ResumeAccountResponse struct {
+	Account string `json:"account"`
+}
+
+// AddAccountRequest 新增账号请求
+type AddAccountRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// AccountsListResponse 账号列表响应
+type AccountsListResponse struct {
+	Accounts []accounts.Account `json:"accounts"`
+	Count    int                `json:"count"`
+}
+
+// PublishDraftRequest 发布草稿请求
+type PublishDraftRequest struct {
+	Title string `json:"title,omitempty"` // 可选，校验草稿标题，避免草稿箱变化后发布错误的草稿
+}
+
+// RescheduleRequest 修改定时发布时间请求
+type RescheduleRequest struct {
+	PublishAt time.Time `json:"publish_at" binding:"required"`
+}
+
+// AccountChallengeResponse 账号待处理的验证码
+type AccountChallengeResponse struct {
+	Account    string    `json:"account"`
+	Reason     string    `json:"reason"`
+	URL        string    `json:"url"`
+	DetectedAt time.Time `json:"detected_at"`
+	Screenshot []byte    `json:"screenshot,omitempty"` // PNG 截图，JSON 中为 Base64
+}
+
+// AuditListResponse 审计日志查询响应
+type AuditListResponse struct {
+	Entries []audit.Entry `json:"entries"`
+	Count   int           `json:"count"`
+}
+
+// SchedulesListResponse 定时发布任务列表响应
+type SchedulesListResponse struct {
+	Tasks []schedule.Task `json:"tasks"`
+	Count int             `json:"count"`
+}
@@ -0,0 +1,81 @@
+package xiaohongshu
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/sirupsen/logrus"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
+)
+
+// 验证页面地址中的特征，触发风控时页面会跳转到 /website-login/captcha 等地址
+var captchaURLKeywords = []string{"/captcha", "verifyUuid=", "verifyType="}
+
+// 验证页面标题中的关键词
+var captchaTitleKeywords = []string{"安全验证", "验证码", "滑块验证"}
+
+// CaptchaError 页面出现滑块验证码或安全验证，需要人工处理
+type CaptchaError struct {
+	URL        string
+	Reason     string
+	Screenshot []byte // PNG 截图，截图失败时为空
+}
+
+func (e *CaptchaError) Error() string {
+	return fmt.Sprintf("遇到验证码（%s），需要人工处理: %s", e.Reason, e.URL)
+}
+
+func (e *CaptchaError) Unwrap() error {
+	return myerrors.ErrCaptchaRequired
+}
+
+// checkCaptcha 在页面跳转后检查是否出现验证码。
+// 检测尽力而为，读取页面信息失败时视为没有验证码，由后续步骤报告具体错误。
+func checkCaptcha(page *rod.Page) error {
+	info, err := page.Info()
+	if err != nil {
+		logrus.Debugf("检查验证码时读取页面信息失败: %v", err)
+		return nil
+	}
+
+	hasWidget, _, err := page.Has(sel().Captcha.Widget)
+	if err != nil {
+		logrus.Debugf("检查验证码组件失败: %v", err)
+	}
+
+	reason := captchaReason(info.URL, info.Title, hasWidget)
+	if reason == "" {
+		return nil
+	}
+
+	logrus.Warnf("检测到验证码: %s, url=%s", reason, info.URL)
+
+	screenshot, err := page.Screenshot(false, &proto.PageCaptureScreenshot{
+		Format: proto.PageCaptureScreenshotFormatPng,
+	})
+	if err != nil {
+		logrus.Warnf("验证码页面截图失败: %v", err)
+	}
+
+	return &CaptchaError{URL: info.URL, Reason: reason, Screenshot: screenshot}
+}
+
+// captchaReason 根据页面地址、标题和验证码组件判断是否为验证页面，返回判断依据，不是验证页面时返回空字符串
+func captchaReason(url, title string, hasWidget bool) string {
+	for _, kw := range captchaURLKeywords {
+		if strings.Contains(url, kw) {
+			return "跳转到验证页面"
+		}
+	}
+	for _, kw := range captchaTitleKeywords {
+		if strings.Contains(title, kw) {
+			return "页面标题: " + title
+		}
+	}
+	if hasWidget {
+		return "出现滑块验证码"
+	}
+	return ""
+}
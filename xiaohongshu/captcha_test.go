@@ -0,0 +1,22 @@
+package xiaohongshu
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
+)
+
+func TestCaptchaReason(t *testing.T) {
+	require.NotEmpty(t, captchaReason("https://www.xiaohongshu.com/website-login/captcha?redirectPath=x", "", false))
+	require.NotEmpty(t, captchaReason("https://www.xiaohongshu.com/explore", "安全验证", false))
+	require.NotEmpty(t, captchaReason("https://www.xiaohongshu.com/explore", "小红书", true))
+	require.Empty(t, captchaReason("https://www.xiaohongshu.com/explore", "小红书 - 你的生活指南", false))
+}
+
+func TestCaptchaErrorCode(t *testing.T) {
+	var err error = &CaptchaError{URL: "https://www.xiaohongshu.com/website-login/captcha", Reason: "跳转到验证页面"}
+	require.True(t, errors.Is(err, myerrors.ErrCaptchaRequired))
+	require.Equal(t, myerrors.CodeCaptchaRequired, myerrors.CodeOf(err))
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
 )
 
 // CommentFeedAction 表示 Feed 评论动作
@@ -23,57 +25,62 @@ func NewCommentFeedAction(page *rod.Page) *CommentFeedAction {
 // PostComment 发表评论到 Feed
 func (f *CommentFeedAction) PostComment(ctx context.Context, feedID, xsecToken, content string) error {
 	// 不使用 Context(ctx)，避免继承外部 context 的超时
-	page := f.page.Timeout(60 * time.Second)
+	page := f.page.Timeout(configs.Get().Timeouts.PageAction.Duration)
 
 	url := makeFeedDetailURL(feedID, xsecToken)
 	logrus.Infof("打开 feed 详情页: %s", url)
 
 	// 导航到详情页
-	page.MustNavigate(url)
-	page.MustWaitDOMStable()
-	time.Sleep(1 * time.Second)
+	if err := navigate(page, "打开笔记详情页", url); err != nil {
+		return err
+	}
+	sleepRange(delays().PageLoad)
 
 	// 检测页面是否可访问
 	if err := checkPageAccessible(page); err != nil {
 		return err
 	}
 
-	elem, err := page.Element("div.input-box div.content-edit span")
+	selectorCommentBox := sel().Comment.InputBox
+	selectorCommentInput := sel().Comment.Input
+	selectorSubmit := sel().Comment.Submit
+
+	elem, err := findElement(page, "评论输入框", selectorCommentBox)
 	if err != nil {
 		logrus.Warnf("Failed to find comment input box: %v", err)
-		return fmt.Errorf("未找到评论输入框，该帖子可能不支持评论或网页端不可访问: %w", err)
+		return fmt.Errorf("该帖子可能不支持评论或网页端不可访问: %w", err)
 	}
 
 	if err := elem.Click(proto.InputMouseButtonLeft, 1); err != nil {
 		logrus.Warnf("Failed to click comment input box: %v", err)
-		return fmt.Errorf("无法点击评论输入框: %w", err)
+		return stepError(page, "点击评论输入框", selectorCommentBox, err)
 	}
 
-	elem2, err := page.Element("div.input-box div.content-edit p.content-input")
+	elem2, err := findElement(page, "评论输入区域", selectorCommentInput)
 	if err != nil {
 		logrus.Warnf("Failed to find comment input field: %v", err)
-		return fmt.Errorf("未找到评论输入区域: %w", err)
+		return err
 	}
 
 	if err := elem2.Input(content); err != nil {
 		logrus.Warnf("Failed to input comment content: %v", err)
-		return fmt.Errorf("无法输入评论内容: %w", err)
+		return stepError(page, "输入评论内容", selectorCommentInput, err)
 	}
 
-	time.Sleep(1 * time.Second)
+	sleepRange(delays().StepPause)
 
-	submitButton, err := page.Element("div.bottom button.submit")
+	submitButton, err := findElement(page, "提交按钮", selectorSubmit)
 	if err != nil {
 		logrus.Warnf("Failed to find submit button: %v", err)
-		return fmt.Errorf("未找到提交按钮: %w", err)
+		return err
 	}
 
 	if err := submitButton.Click(proto.InputMouseButtonLeft, 1); err != nil {
 		logrus.Warnf("Failed to click submit button: %v", err)
-		return fmt.Errorf("无法点击提交按钮: %w", err)
+		return stepError(page, "点击提交按钮", selectorSubmit, err)
 	}
 
-	time.Sleep(1 * time.Second)
+	sleepRange(delays().ActionSettle)
 
 	logrus.Infof("Comment posted successfully to feed: %s", feedID)
 	return nil
@@ -83,14 +90,15 @@ func (f *CommentFeedAction) PostComment(ctx context.Context, feedID, xsecToken,
 func (f *CommentFeedAction) ReplyToComment(ctx context.Context, feedID, xsecToken, commentID, userID, content string) error {
 	// 增加超时时间，因为需要滚动查找评论
 	// 注意：不使用 Context(ctx)，避免继承外部 context 的超时
-	page := f.page.Timeout(5 * time.Minute)
+	page := f.page.Timeout(configs.Get().Timeouts.ReplyComment.Duration)
 	url := makeFeedDetailURL(feedID, xsecToken)
 	logrus.Infof("打开 feed 详情页进行回复: %s", url)
 
 	// 导航到详情页
-	page.MustNavigate(url)
-	page.MustWaitDOMStable()
-	time.Sleep(1 * time.Second)
+	if err := navigate(page, "打开笔记详情页", url); err != nil {
+		return err
+	}
+	sleepRange(delays().PageLoad)
 
 	// 检测页面是否可访问
 	if err := checkPageAccessible(page); err != nil {
@@ -98,7 +106,7 @@ func (f *CommentFeedAction) ReplyToComment(ctx context.Context, feedID, xsecToke
 	}
 
 	// 等待评论容器加载
-	time.Sleep(2 * time.Second)
+	sleepRange(delays().ActionSettle)
 
 	// 使用 Go 实现的查找逻辑
 	commentEl, err := findCommentElement(page, commentID, userID)
@@ -108,47 +116,54 @@ func (f *CommentFeedAction) ReplyToComment(ctx context.Context, feedID, xsecToke
 
 	// 滚动到评论位置
 	logrus.Info("滚动到评论位置...")
-	commentEl.MustScrollIntoView()
-	time.Sleep(1 * time.Second)
+	if err := commentEl.ScrollIntoView(); err != nil {
+		return stepError(page, "滚动到评论", "", err)
+	}
+	sleepRange(delays().StepPause)
 
 	logrus.Info("准备点击回复按钮")
 
 	// 查找并点击回复按钮
-	replyBtn, err := commentEl.Element(".right .interactions .reply")
+	selectorReply := sel().Comment.ReplyButton
+	selectorReplyInput := sel().Comment.Input
+	selectorSubmit := sel().Comment.Submit
+
+	replyBtn, err := commentEl.Element(selectorReply)
 	if err != nil {
-		return fmt.Errorf("无法找到回复按钮: %w", err)
+		return stepError(page, "查找回复按钮", selectorReply,
+			myerrors.Wrap(myerrors.CodeSelectorNotFound, err, "页面元素未找到"))
 	}
 
 	if err := replyBtn.Click(proto.InputMouseButtonLeft, 1); err != nil {
-		return fmt.Errorf("点击回复按钮失败: %w", err)
+		return stepError(page, "点击回复按钮", selectorReply, err)
 	}
 
-	time.Sleep(1 * time.Second)
+	sleepRange(delays().StepPause)
 
 	// 查找回复输入框
-	inputEl, err := page.Element("div.input-box div.content-edit p.content-input")
+	inputEl, err := findElement(page, "回复输入框", selectorReplyInput)
 	if err != nil {
-		return fmt.Errorf("无法找到回复输入框: %w", err)
+		return err
 	}
 
 	// 输入内容
 	if err := inputEl.Input(content); err != nil {
-		return fmt.Errorf("输入回复内容失败: %w", err)
+		return stepError(page, "输入回复内容", selectorReplyInput, err)
 	}
 
-	time.Sleep(500 * time.Millisecond)
+	sleepRange(delays().StepPause)
 
 	// 查找并点击提交按钮
-	submitBtn, err := page.Element("div.bottom button.submit")
+	submitBtn, err := findElement(page, "提交按钮", selectorSubmit)
 	if err != nil {
-		return fmt.Errorf("无法找到提交按钮: %w", err)
+		return err
 	}
 
 	if err := submitBtn.Click(proto.InputMouseButtonLeft, 1); err != nil {
-		return fmt.Errorf("点击提交按钮失败: %w", err)
+		return stepError(page, "点击提交按钮", selectorSubmit, err)
 	}
 
-	time.Sleep(2 * time.Second)
+	sleepRange(delays().ActionSettle)
 	logrus.Infof("回复评论成功")
 	return nil
 }
@@ -162,7 +177,7 @@ func findCommentElement(page *rod.Page, commentID, userID string) (*rod.Element,
 
 	// 先滚动到评论区
 	scrollToCommentsArea(page)
-	time.Sleep(1 * time.Second)
+	sleepRange(delays().StepPause)
 
 	var lastCommentCount = 0
 	stagnantChecks := 0
@@ -204,7 +219,7 @@ func findCommentElement(page *rod.Page, commentID, userID string) (*rod.Element,
 			logrus.Infof("滚动到最后一个评论（共 %d 条）", currentCount)
 			
 			// 使用 Go 获取所有评论元素
-			elements, err := page.Timeout(2 * time.Second).Elements(".parent-comment, .comment-item, .comment")
+			elements, err := page.Timeout(2 * time.Second).Elements(sel().Comment.Item)
 			if err == nil && len(elements) > 0 {
 				// 滚动到最后一个评论
 				lastComment := elements[len(elements)-1]
@@ -229,7 +244,7 @@ func findCommentElement(page *rod.Page, commentID, userID string) (*rod.Element,
 		// === 6. 滚动后立即查找（边滚动边查找）===
 		// 优先通过 commentID 查找（使用 Timeout 避免长时间等待）
 		if commentID != "" {
-			selector := fmt.Sprintf("#comment-%s", commentID)
+			selector := fmt.Sprintf(sel().Comment.ByID, commentID)
 			logrus.Infof("尝试通过 commentID 查找: %s", selector)
 			
 			// 使用 Timeout 避免长时间等待
@@ -246,7 +261,7 @@ func findCommentElement(page *rod.Page, commentID, userID string) (*rod.Element,
 			logrus.Infof("尝试通过 userID 查找: %s", userID)
 			
 			// 使用 Timeout 避免长时间等待
-			elements, err := page.Timeout(2 * time.Second).Elements(".comment-item, .comment, .parent-comment")
+			elements, err := page.Timeout(2 * time.Second).Elements(sel().Comment.Item)
 			if err == nil && len(elements) > 0 {
 				logrus.Infof("找到 %d 个评论元素", len(elements))
 				for i, el := range elements {
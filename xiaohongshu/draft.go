@@ -0,0 +1,273 @@
+package xiaohongshu
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// DraftType 草稿类型
+type DraftType string
+
+const (
+	DraftTypeImage DraftType = "image"
+	DraftTypeVideo DraftType = "video"
+)
+
+// ParseDraftType 解析草稿类型，空字符串默认为图文
+func ParseDraftType(s string) (DraftType, error) {
+	switch DraftType(s) {
+	case "", DraftTypeImage:
+		return DraftTypeImage, nil
+	case DraftTypeVideo:
+		return DraftTypeVideo, nil
+	default:
+		return "", errors.Errorf("未知的草稿类型: %s，可选 image|video", s)
+	}
+}
+
+// Draft 创作中心草稿箱中的一条草稿
+type Draft struct {
+	Index     int       `json:"index"` // 在草稿箱中的位置，从 0 开始，最新保存的在前
+	Type      DraftType `json:"type"`
+	Title     string    `json:"title"`
+	UpdatedAt string    `json:"updated_at,omitempty"` // 页面显示的保存时间
+}
+
+// DraftRef 定位一条草稿。
+// 草稿箱没有稳定的ID，按位置定位；指定 Title 时会校验标题，避免草稿箱变化后操作到其他草稿。
+type DraftRef struct {
+	Type  DraftType
+	Index int
+	Title string
+}
+
+// DraftContent 打开草稿后编辑器中的内容
+type DraftContent struct {
+	Type    DraftType `json:"type"`
+	Title   string    `json:"title"`
+	Content string    `json:"content"`
+}
+
+const (
+	// 发布页中的草稿箱入口及草稿的编辑按钮
+	draftBoxEntryText   = "草稿箱"
+	draftEditButtonText = "编辑"
+	saveDraftButtonText = "暂存离开"
+)
+
+var draftTabNames = map[DraftType]string{
+	DraftTypeImage: "图文笔记",
+	DraftTypeVideo: "视频笔记",
+}
+
+// saveDraft 点击“暂存离开”保存当前编辑内容
+func saveDraft(page *rod.Page) error {
+	btn, err := page.Timeout(configs.Get().Timeouts.ElementWait.Duration).ElementR(sel().Draft.SaveButton, saveDraftButtonText)
+	if err != nil {
+		return errors.Wrap(err, "没有找到暂存按钮")
+	}
+
+	if err := btn.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return errors.Wrap(err, "点击暂存按钮失败")
+	}
+
+	// 暂存后页面会离开编辑器
+	if err := page.Timeout(configs.Get().Timeouts.ElementWait.Duration).WaitElementsMoreThan(sel().Publish.UploadContent, 0); err != nil {
+		return errors.Wrap(err, "等待暂存完成超时")
+	}
+
+	logrus.Info("草稿已保存")
+	return nil
+}
+
+// DraftAction 创作中心草稿箱操作
+type DraftAction struct {
+	page *rod.Page
+}
+
+// NewDraftAction 进入发布页
+func NewDraftAction(page *rod.Page) (*DraftAction, error) {
+	pp := page.Timeout(configs.Get().Timeouts.Upload.Duration)
+
+	if err := openPublishPage(pp); err != nil {
+		return nil, err
+	}
+	time.Sleep(1 * time.Second)
+
+	if err := checkCreatorLogin(pp); err != nil {
+		return nil, err
+	}
+	if err := checkCaptcha(pp); err != nil {
+		return nil, err
+	}
+
+	return &DraftAction{page: pp}, nil
+}
+
+// List 列出草稿箱中指定类型的草稿
+func (d *DraftAction) List(ctx context.Context, typ DraftType) ([]Draft, error) {
+	page := d.page.Context(ctx)
+
+	items, err := openDraftBox(page, typ)
+	if err != nil {
+		return nil, err
+	}
+
+	drafts := make([]Draft, 0, len(items))
+	for i, item := range items {
+		drafts = append(drafts, Draft{
+			Index:     i,
+			Type:      typ,
+			Title:     elementText(item, sel().Draft.Title),
+			UpdatedAt: elementText(item, sel().Draft.Time),
+		})
+	}
+
+	return drafts, nil
+}
+
+// Open 打开草稿进入编辑器，返回编辑器中的内容
+func (d *DraftAction) Open(ctx context.Context, ref DraftRef) (*DraftContent, error) {
+	page := d.page.Context(ctx)
+
+	if err := openDraft(page, ref); err != nil {
+		return nil, err
+	}
+
+	return readEditorContent(page, ref.Type)
+}
+
+// Publish 打开草稿并直接发布
+func (d *DraftAction) Publish(ctx context.Context, ref DraftRef) (*PublishResult, error) {
+	page := d.page.Context(ctx)
+
+	if err := openDraft(page, ref); err != nil {
+		return nil, err
+	}
+
+	var (
+		result *PublishResult
+		err    error
+	)
+	if ref.Type == DraftTypeVideo {
+		result, err = submitPublishVideo(page, func(PublishStage) {})
+	} else {
+		result, err = submitPublish(page, func(PublishStage) {})
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "小红书发布草稿失败")
+	}
+
+	lookupPublishedNote(ctx, page, result)
+	return result, nil
+}
+
+// openDraftBox 打开草稿箱并切换到对应类型，返回草稿列表元素
+func openDraftBox(page *rod.Page, typ DraftType) (rod.Elements, error) {
+	entry, err := page.Timeout(configs.Get().Timeouts.ElementWait.Duration).ElementR(sel().Draft.BoxEntry, "^"+draftBoxEntryText)
+	if err != nil {
+		return nil, errors.Wrap(err, "没有找到草稿箱入口")
+	}
+	if err := entry.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return nil, errors.Wrap(err, "打开草稿箱失败")
+	}
+	time.Sleep(1 * time.Second)
+
+	if tab, err := page.Timeout(configs.Get().Timeouts.ElementProbe.Duration).ElementR(sel().Draft.TypeTab, "^"+draftTabNames[typ]); err == nil {
+		if err := tab.Click(proto.InputMouseButtonLeft, 1); err != nil {
+			return nil, errors.Wrap(err, "切换草稿类型失败")
+		}
+		time.Sleep(1 * time.Second)
+	} else {
+		logrus.Warnf("没有找到草稿类型标签 %s，使用当前列表", draftTabNames[typ])
+	}
+
+	items, err := page.Elements(sel().Draft.Item)
+	if err != nil {
+		return nil, errors.Wrap(err, "读取草稿列表失败")
+	}
+
+	return items, nil
+}
+
+// openDraft 定位草稿并点击编辑
+func openDraft(page *rod.Page, ref DraftRef) error {
+	items, err := openDraftBox(page, ref.Type)
+	if err != nil {
+		return err
+	}
+
+	if ref.Index < 0 || ref.Index >= len(items) {
+		return errors.Errorf("草稿不存在: index=%d，当前共 %d 条草稿", ref.Index, len(items))
+	}
+	item := items[ref.Index]
+
+	if ref.Title != "" {
+		if title := elementText(item, sel().Draft.Title); title != ref.Title {
+			return errors.Errorf("草稿标题不匹配: 期望 %q，实际 %q，草稿箱可能已变化，请重新获取草稿列表", ref.Title, title)
+		}
+	}
+
+	edit, err := item.ElementR(sel().Draft.EditButton, "^"+regexp.QuoteMeta(draftEditButtonText)+"$")
+	if err != nil {
+		// 部分样式下点击整条草稿即可进入编辑
+		edit = item
+	}
+	if err := edit.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return errors.Wrap(err, "打开草稿失败")
+	}
+
+	// 等待编辑器加载
+	if _, err := page.Timeout(configs.Get().Timeouts.EditorLoad.Duration).Element(sel().Publish.TitleInput); err != nil {
+		return errors.Wrap(err, "等待草稿编辑器加载超时")
+	}
+	time.Sleep(1 * time.Second)
+
+	return nil
+}
+
+// readEditorContent 读取编辑器中的标题和正文
+func readEditorContent(page *rod.Page, typ DraftType) (*DraftContent, error) {
+	titleElem, err := page.Element(sel().Publish.TitleInput)
+	if err != nil {
+		return nil, errors.Wrap(err, "没有找到标题输入框")
+	}
+	title, err := titleElem.Property("value")
+	if err != nil {
+		return nil, errors.Wrap(err, "读取标题失败")
+	}
+
+	content := ""
+	if contentElem, err := getContentElement(page); err == nil {
+		if text, err := contentElem.Text(); err == nil {
+			content = strings.TrimSpace(text)
+		}
+	}
+
+	return &DraftContent{
+		Type:    typ,
+		Title:   title.String(),
+		Content: content,
+	}, nil
+}
+
+// elementText 读取子元素文本，不存在时返回空字符串
+func elementText(elem *rod.Element, selector string) string {
+	has, child, err := elem.Has(selector)
+	if err != nil || !has {
+		return ""
+	}
+	text, err := child.Text()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(text)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
 	"github.com/xpzouying/xiaohongshu-mcp/errors"
 )
 
@@ -29,21 +30,11 @@ const (
 	finalSprintPushCount   = 15
 )
 
-// 延迟时间配置（毫秒）
-type delayConfig struct {
-	min, max int
+// delays 返回当前配置的模拟真人操作的随机延迟
+func delays() configs.DelaysConfig {
+	return configs.Get().Delays
 }
 
-var (
-	humanDelayRange   = delayConfig{300, 700}
-	reactionTimeRange = delayConfig{300, 800}
-	hoverTimeRange    = delayConfig{100, 300}
-	readTimeRange     = delayConfig{500, 1200}
-	shortReadRange    = delayConfig{600, 1200}
-	scrollWaitRange   = delayConfig{100, 200}
-	postScrollRange   = delayConfig{300, 500}
-)
-
 // ========== 数据结构 ==========
 
 type CommentLoadConfig struct {
@@ -63,13 +54,19 @@ func DefaultCommentLoadConfig() CommentLoadConfig {
 }
 
 type FeedDetailAction struct {
-	page *rod.Page
+	page       *rod.Page
+	onProgress func(loaded, target int)
 }
 
 func NewFeedDetailAction(page *rod.Page) *FeedDetailAction {
 	return &FeedDetailAction{page: page}
 }
 
+// OnProgress 设置评论加载进度回调，loaded 为已加载的一级评论数，target 为目标数量（未知时为 0）
+func (f *FeedDetailAction) OnProgress(fn func(loaded, target int)) {
+	f.onProgress = fn
+}
+
 // ========== 主要业务逻辑 ==========
 
 func (f *FeedDetailAction) GetFeedDetail(ctx context.Context, feedID, xsecToken string, loadAllComments bool, config CommentLoadConfig) (*FeedDetailResponse, error) {
@@ -77,7 +74,7 @@ func (f *FeedDetailAction) GetFeedDetail(ctx context.Context, feedID, xsecToken
 }
 
 func (f *FeedDetailAction) GetFeedDetailWithConfig(ctx context.Context, feedID, xsecToken string, loadAllComments bool, config CommentLoadConfig) (*FeedDetailResponse, error) {
-	page := f.page.Context(ctx).Timeout(10 * time.Minute)
+	page := f.page.Context(ctx).Timeout(configs.Get().Timeouts.FeedDetail.Duration)
 	url := makeFeedDetailURL(feedID, xsecToken)
 
 	logrus.Infof("打开 feed 详情页: %s", url)
@@ -87,9 +84,7 @@ func (f *FeedDetailAction) GetFeedDetailWithConfig(ctx context.Context, feedID,
 	// 使用retry-go处理页面导航和DOM稳定等待
 	err := retry.Do(
 		func() error {
-			page.MustNavigate(url)
-			page.MustWaitDOMStable()
-			return nil
+			return navigate(page, "打开笔记详情页", url)
 		},
 		retry.Attempts(3),
 		retry.Delay(500*time.Millisecond),
@@ -104,6 +99,9 @@ func (f *FeedDetailAction) GetFeedDetailWithConfig(ctx context.Context, feedID,
 	}
 	sleepRandom(1000, 1000)
 
+	if err := checkCaptcha(page); err != nil {
+		return nil, err
+	}
 	if err := checkPageAccessible(page); err != nil {
 		return nil, err
 	}
@@ -120,10 +118,11 @@ func (f *FeedDetailAction) GetFeedDetailWithConfig(ctx context.Context, feedID,
 // ========== 评论加载器 ==========
 
 type commentLoader struct {
-	page   *rod.Page
-	config CommentLoadConfig
-	stats  *loadStats
-	state  *loadState
+	page       *rod.Page
+	config     CommentLoadConfig
+	stats      *loadStats
+	state      *loadState
+	onProgress func(loaded, target int)
 }
 
 type loadStats struct {
@@ -140,10 +139,11 @@ type loadState struct {
 
 func (f *FeedDetailAction) loadAllCommentsWithConfig(page *rod.Page, config CommentLoadConfig) error {
 	loader := &commentLoader{
-		page:   page,
-		config: config,
-		stats:  &loadStats{},
-		state:  &loadState{},
+		page:       page,
+		config:     config,
+		stats:      &loadStats{},
+		state:      &loadState{},
+		onProgress: f.onProgress,
 	}
 
 	return loader.load()
@@ -155,7 +155,7 @@ func (cl *commentLoader) load() error {
 
 	logrus.Info("开始加载评论...")
 	scrollToCommentsArea(cl.page)
-	sleepRandom(humanDelayRange.min, humanDelayRange.max)
+	sleepRange(delays().Human)
 
 	// 检查是否没有评论
 	if cl.checkNoComments() {
@@ -209,7 +209,7 @@ func (cl *commentLoader) checkComplete() bool {
 	if checkEndContainer(cl.page) {
 		currentCount := getCommentCount(cl.page)
 		logrus.Infof("✓ 检测到 'THE END' 元素，已滑动到底部")
-		sleepRandom(humanDelayRange.min, humanDelayRange.max)
+		sleepRange(delays().Human)
 		logrus.Infof("✓ 加载完成: %d 条评论, 尝试次数: %d, 点击: %d, 跳过: %d",
 			currentCount, cl.stats.attempts+1, cl.stats.totalClicked, cl.stats.totalSkipped)
 		return true
@@ -229,7 +229,7 @@ func (cl *commentLoader) clickButtonsWithRetry() {
 		logrus.Infof("点击'更多': %d 个, 跳过: %d 个, 累计点击: %d, 累计跳过: %d",
 			clicked, skipped, cl.stats.totalClicked, cl.stats.totalSkipped)
 
-		sleepRandom(readTimeRange.min, readTimeRange.max)
+		sleepRange(delays().Read)
 
 		// 重试一轮
 		clicked2, skipped2 := clickShowMoreButtonsSmart(cl.page, cl.config.MaxRepliesThreshold)
@@ -237,7 +237,7 @@ func (cl *commentLoader) clickButtonsWithRetry() {
 			cl.stats.totalClicked += clicked2
 			cl.stats.totalSkipped += skipped2
 			logrus.Infof("第 2 轮: 点击 %d, 跳过 %d", clicked2, skipped2)
-			sleepRandom(shortReadRange.min, shortReadRange.max)
+			sleepRange(delays().ShortRead)
 		}
 	}
 }
@@ -251,6 +251,7 @@ func (cl *commentLoader) updateState(currentCount int) {
 			cl.state.lastCount, currentCount, currentCount-cl.state.lastCount)
 		cl.state.lastCount = currentCount
 		cl.state.stagnantChecks = 0
+		cl.reportProgress(currentCount, totalCount)
 	} else {
 		cl.state.stagnantChecks++
 		if cl.state.stagnantChecks%5 == 0 {
@@ -259,6 +260,21 @@ func (cl *commentLoader) updateState(currentCount int) {
 	}
 }
 
+// reportProgress 上报已加载的评论数。指定了 MaxCommentItems 时以其为目标，否则以页面显示的评论总数为目标
+func (cl *commentLoader) reportProgress(currentCount, totalCount int) {
+	if cl.onProgress == nil {
+		return
+	}
+	target := totalCount
+	if cl.config.MaxCommentItems > 0 {
+		target = cl.config.MaxCommentItems
+	}
+	if target > 0 && currentCount > target {
+		target = currentCount
+	}
+	cl.onProgress(currentCount, target)
+}
+
 func (cl *commentLoader) shouldStopAtTarget(currentCount int) bool {
 	// 如果未设置最大评论数，或者还未达到目标，继续加载
 	if cl.config.MaxCommentItems <= 0 {
@@ -279,7 +295,7 @@ func (cl *commentLoader) performScroll() {
 	currentCount := getCommentCount(cl.page)
 	if currentCount > 0 {
 		scrollToLastComment(cl.page)
-		sleepRandom(postScrollRange.min, postScrollRange.max)
+		sleepRange(delays().PostScroll)
 	}
 
 	largeMode := cl.state.stagnantChecks >= largeScrollTrigger
@@ -335,6 +351,11 @@ func sleepRandom(minMs, maxMs int) {
 	time.Sleep(delay)
 }
 
+// sleepRange 在延迟范围内随机等待
+func sleepRange(r configs.DelayRange) {
+	sleepRandom(int(r.Min.Milliseconds()), int(r.Max.Milliseconds()))
+}
+
 func getScrollInterval(speed string) time.Duration {
 	switch speed {
 	case "slow":
@@ -349,7 +370,7 @@ func getScrollInterval(speed string) time.Duration {
 // ========== 按钮点击 ==========
 
 func clickShowMoreButtonsSmart(page *rod.Page, maxRepliesThreshold int) (clicked, skipped int) {
-	elements, err := page.Elements(".show-more")
+	elements, err := page.Elements(sel().FeedDetail.ShowMore)
 	if err != nil {
 		return 0, 0
 	}
@@ -418,20 +439,24 @@ func clickElementWithHumanBehavior(page *rod.Page, el *rod.Element, text string)
 	err := retry.Do(
 		func() error {
 			// 滚动到元素
-			el.MustEval(`() => {
+			if _, err := el.Eval(`() => {
 				try {
 					this.scrollIntoView({behavior: 'smooth', block: 'center'});
 				} catch (e) {}
-			}`)
+			}`); err != nil {
+				return err
+			}
 
-			sleepRandom(reactionTimeRange.min, reactionTimeRange.max)
+			sleepRange(delays().Reaction)
 
 			// 鼠标悬停
 			if box, err := el.Shape(); err == nil && len(box.Quads) > 0 {
 				x := float64(box.Quads[0][0]+box.Quads[0][4]) / 2
 				y := float64(box.Quads[0][1]+box.Quads[0][5]) / 2
-				page.Mouse.MustMoveTo(x, y)
-				sleepRandom(hoverTimeRange.min, hoverTimeRange.max)
+				if err := page.Mouse.MoveTo(proto.NewPoint(x, y)); err != nil {
+					return err
+				}
+				sleepRange(delays().Hover)
 			}
 
 			// 点击
@@ -440,7 +465,7 @@ func clickElementWithHumanBehavior(page *rod.Page, el *rod.Element, text string)
 			}
 
 			// 模拟人类阅读时间
-			sleepRandom(readTimeRange.min, readTimeRange.max)
+			sleepRange(delays().Read)
 			clickSuccess = true
 			return nil
 		},
@@ -468,7 +493,12 @@ func clickElementWithHumanBehavior(page *rod.Page, el *rod.Element, text string)
 
 func humanScroll(page *rod.Page, speed string, largeMode bool, pushCount int) (bool, int, int) {
 	beforeTop := getScrollTop(page)
-	viewportHeight := page.MustEval(`() => window.innerHeight`).Int()
+	viewportHeight := 800
+	if res, err := page.Eval(`() => window.innerHeight`); err == nil {
+		viewportHeight = res.Value.Int()
+	} else {
+		logrus.Debugf("获取视口高度失败，使用默认值: %v", err)
+	}
 
 	baseRatio := getScrollRatio(speed)
 	if largeMode {
@@ -481,9 +511,11 @@ func humanScroll(page *rod.Page, speed string, largeMode bool, pushCount int) (b
 
 	for i := 0; i < max(1, pushCount); i++ {
 		scrollDelta := calculateScrollDelta(viewportHeight, baseRatio)
-		page.MustEval(`(delta) => { window.scrollBy(0, delta); }`, scrollDelta)
+		if _, err := page.Eval(`(delta) => { window.scrollBy(0, delta); }`, scrollDelta); err != nil {
+			logrus.Debugf("滚动失败: %v", err)
+		}
 
-		sleepRandom(scrollWaitRange.min, scrollWaitRange.max)
+		sleepRange(delays().ScrollWait)
 
 		currentScrollTop = getScrollTop(page)
 		deltaThisTime := currentScrollTop - beforeTop
@@ -496,13 +528,15 @@ func humanScroll(page *rod.Page, speed string, largeMode bool, pushCount int) (b
 		beforeTop = currentScrollTop
 
 		if i < pushCount-1 {
-			sleepRandom(humanDelayRange.min, humanDelayRange.max)
+			sleepRange(delays().Human)
 		}
 	}
 
 	if !scrolled && pushCount > 0 {
-		page.MustEval(`() => window.scrollTo(0, document.body.scrollHeight)`)
-		sleepRandom(postScrollRange.min, postScrollRange.max)
+		if _, err := page.Eval(`() => window.scrollTo(0, document.body.scrollHeight)`); err != nil {
+			logrus.Debugf("滚动到底部失败: %v", err)
+		}
+		sleepRange(delays().PostScroll)
 		currentScrollTop = getScrollTop(page)
 		actualDelta = currentScrollTop - beforeTop + actualDelta
 		scrolled = actualDelta > 5
@@ -539,8 +573,10 @@ func scrollToCommentsArea(page *rod.Page) {
 	logrus.Info("滚动到评论区...")
 
 	// 先定位到评论区
-	if el, err := page.Timeout(2 * time.Second).Element(".comments-container"); err == nil {
-		el.MustScrollIntoView()
+	if el, err := page.Timeout(2 * time.Second).Element(sel().FeedDetail.CommentsContainer); err == nil {
+		if err := el.ScrollIntoView(); err != nil {
+			logrus.Debugf("滚动到评论区失败: %v", err)
+		}
 	}
 	// 等待滚动完成
 	time.Sleep(500 * time.Millisecond)
@@ -551,11 +587,16 @@ func scrollToCommentsArea(page *rod.Page) {
 
 // smartScroll 智能滚动：触发滚轮事件以正确触发懒加载
 func smartScroll(page *rod.Page, delta float64) {
-	page.MustEval(`(delta) => {
-		// 查找滚动目标元素
-		let targetElement = document.querySelector('.note-scroller') 
-			|| document.querySelector('.interaction-container') 
-			|| document.documentElement;
+	_, err := page.Eval(`(delta, selectors) => {
+		// 查找滚动目标元素，按配置的顺序尝试
+		let targetElement = document.documentElement;
+		for (const selector of selectors.split(',')) {
+			const el = document.querySelector(selector.trim());
+			if (el) {
+				targetElement = el;
+				break;
+			}
+		}
 		
 		// 触发滚轮事件（关键！这样才能触发懒加载）
 		const wheelEvent = new WheelEvent('wheel', {
@@ -566,18 +607,23 @@ func smartScroll(page *rod.Page, delta float64) {
 			view: window
 		});
 		targetElement.dispatchEvent(wheelEvent);
-	}`, delta)
+	}`, delta, sel().FeedDetail.Scroller)
+	if err != nil {
+		logrus.Debugf("触发滚轮事件失败: %v", err)
+	}
 }
 
 func scrollToLastComment(page *rod.Page) {
 	// 获取所有主评论元素
-	elements, err := page.Timeout(2 * time.Second).Elements(".parent-comment")
+	elements, err := page.Timeout(2 * time.Second).Elements(sel().FeedDetail.ParentComment)
 	if err != nil || len(elements) == 0 {
 		return
 	}
 	// 滚动到最后一个评论
 	lastComment := elements[len(elements)-1]
-	lastComment.MustScrollIntoView()
+	if err := lastComment.ScrollIntoView(); err != nil {
+		logrus.Debugf("滚动到最后一条评论失败: %v", err)
+	}
 }
 
 // ========== DOM 查询 ==========
@@ -588,11 +634,14 @@ func getScrollTop(page *rod.Page) int {
 	// 使用retry-go来处理可能的DOM查询失败
 	err := retry.Do(
 		func() error {
-			evalResult := page.MustEval(`() => {
+			evalResult, err := page.Eval(`() => {
 				return window.pageYOffset || document.documentElement.scrollTop || document.body.scrollTop || 0;
 			}`)
+			if err != nil {
+				return err
+			}
 
-			result = evalResult.Int()
+			result = evalResult.Value.Int()
 			return nil
 		},
 		retry.Attempts(3),
@@ -618,7 +667,7 @@ func getCommentCount(page *rod.Page) int {
 	err := retry.Do(
 		func() error {
 			// 使用 Go 获取评论元素
-			elements, err := page.Timeout(2 * time.Second).Elements(".parent-comment")
+			elements, err := page.Timeout(2 * time.Second).Elements(sel().FeedDetail.ParentComment)
 			if err != nil {
 				return err
 			}
@@ -648,7 +697,7 @@ func getTotalCommentCount(page *rod.Page) int {
 	err := retry.Do(
 		func() error {
 			// 使用 Go 获取总评论数元素
-			totalEl, err := page.Timeout(2 * time.Second).Element(".comments-container .total")
+			totalEl, err := page.Timeout(2 * time.Second).Element(sel().FeedDetail.TotalComments)
 			if err != nil {
 				return err
 			}
@@ -692,7 +741,7 @@ func getTotalCommentCount(page *rod.Page) int {
 
 func checkNoCommentsArea(page *rod.Page) bool {
 	// 查找无评论区域
-	noCommentsEl, err := page.Timeout(2 * time.Second).Element(".no-comments-text")
+	noCommentsEl, err := page.Timeout(2 * time.Second).Element(sel().FeedDetail.NoComments)
 	if err != nil {
 		// 未找到无评论元素，说明有评论或评论区正常
 		return false
@@ -716,7 +765,7 @@ func checkEndContainer(page *rod.Page) bool {
 	err := retry.Do(
 		func() error {
 			// 使用 Go 查找结束容器
-			endEl, err := page.Timeout(2 * time.Second).Element(".end-container")
+			endEl, err := page.Timeout(2 * time.Second).Element(sel().FeedDetail.End)
 			if err != nil {
 				// 未找到元素，说明未到底部
 				result = false
@@ -753,11 +802,22 @@ func checkEndContainer(page *rod.Page) bool {
 
 // ========== 页面检查 ==========
 
+// inaccessibleKeywords 笔记不可访问时错误提示中的关键词
+var inaccessibleKeywords = []struct {
+	code     errors.Code
+	keywords []string
+}{
+	{errors.CodeNoteDeleted, []string{"该内容因违规已被删除", "该笔记已被删除", "内容不存在", "笔记不存在", "已失效"}},
+	{errors.CodeNotePrivate, []string{"私密笔记", "仅作者可见", "因用户设置，你无法查看"}},
+	{errors.CodeRiskControl, []string{"访问频繁", "安全限制"}},
+	{errors.CodeNoteUnavailable, []string{"当前笔记暂时无法浏览", "因违规无法查看"}},
+}
+
 func checkPageAccessible(page *rod.Page) error {
 	time.Sleep(500 * time.Millisecond)
 
 	// 查找错误提示容器
-	wrapperEl, err := page.Timeout(2 * time.Second).Element(".access-wrapper, .error-wrapper, .not-found-wrapper, .blocked-wrapper")
+	wrapperEl, err := page.Timeout(2 * time.Second).Element(sel().FeedDetail.Inaccessible)
 	if err != nil {
 		// 未找到错误容器，说明页面可访问
 		return nil
@@ -770,24 +830,13 @@ func checkPageAccessible(page *rod.Page) error {
 		return nil
 	}
 
-	// 检查关键词
-	keywords := []string{
-		"当前笔记暂时无法浏览",
-		"该内容因违规已被删除",
-		"该笔记已被删除",
-		"内容不存在",
-		"笔记不存在",
-		"已失效",
-		"私密笔记",
-		"仅作者可见",
-		"因用户设置，你无法查看",
-		"因违规无法查看",
-	}
-
-	for _, kw := range keywords {
-		if strings.Contains(text, kw) {
-			logrus.Warnf("笔记不可访问: %s", kw)
-			return fmt.Errorf("笔记不可访问: %s", kw)
+	// 检查关键词，按不可访问的原因返回对应的错误代码
+	for _, group := range inaccessibleKeywords {
+		for _, kw := range group.keywords {
+			if strings.Contains(text, kw) {
+				logrus.Warnf("笔记不可访问: %s", kw)
+				return errors.New(group.code, "笔记不可访问: %s", kw)
+			}
 		}
 	}
 
@@ -795,7 +844,7 @@ func checkPageAccessible(page *rod.Page) error {
 	trimmedText := strings.TrimSpace(text)
 	if trimmedText != "" {
 		logrus.Warnf("笔记不可访问（未知原因）: %s", trimmedText)
-		return fmt.Errorf("笔记不可访问: %s", trimmedText)
+		return errors.New(errors.CodeNoteUnavailable, "笔记不可访问: %s", trimmedText)
 	}
 
 	return nil
@@ -809,7 +858,7 @@ func (f *FeedDetailAction) extractFeedDetail(page *rod.Page, feedID string) (*Fe
 	// 使用retry-go来处理可能的DOM查询失败
 	err := retry.Do(
 		func() error {
-			evalResult := page.MustEval(`() => {
+			evalResult, err := evalJS(page, "读取笔记详情数据", `() => {
 				if (window.__INITIAL_STATE__ &&
 					window.__INITIAL_STATE__.note &&
 					window.__INITIAL_STATE__.note.noteDetailMap) {
@@ -817,10 +866,13 @@ func (f *FeedDetailAction) extractFeedDetail(page *rod.Page, feedID string) (*Fe
 					return JSON.stringify(noteDetailMap);
 				}
 				return "";
-			}`).String()
+			}`)
+			if err != nil {
+				return err
+			}
 
-			if evalResult != "" {
-				result = evalResult
+			if evalResult.String() != "" {
+				result = evalResult.String()
 				return nil
 			}
 			return fmt.Errorf("无法获取初始状态数据")
@@ -4,23 +4,87 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/go-rod/rod"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
 	"github.com/xpzouying/xiaohongshu-mcp/errors"
 )
 
+// FeedChannel 首页发现页的频道
+type FeedChannel struct {
+	Name  string `json:"name"`  // 参数中使用的名称
+	Label string `json:"label"` // 页面上显示的名称
+	ID    string `json:"id"`    // 页面的 channel_id
+}
+
+// FeedChannels 支持的发现页频道，第一个为默认的推荐频道
+var FeedChannels = []FeedChannel{
+	{Name: "recommend", Label: "推荐", ID: "homefeed_recommend"},
+	{Name: "fashion", Label: "穿搭", ID: "homefeed.fashion_v3"},
+	{Name: "food", Label: "美食", ID: "homefeed.food_v3"},
+	{Name: "cosmetics", Label: "彩妆", ID: "homefeed.cosmetics_v3"},
+	{Name: "movie", Label: "影视", ID: "homefeed.movie_and_tv_v3"},
+	{Name: "career", Label: "职场", ID: "homefeed.career_v3"},
+	{Name: "love", Label: "情感", ID: "homefeed.love_v3"},
+	{Name: "home", Label: "家居", ID: "homefeed.household_product_v3"},
+	{Name: "gaming", Label: "游戏", ID: "homefeed.gaming_v3"},
+	{Name: "travel", Label: "旅行", ID: "homefeed.travel_v3"},
+	{Name: "fitness", Label: "健身", ID: "homefeed.fitness_v3"},
+}
+
+// ParseFeedChannel 按名称、中文名或 channel_id 查找频道，空字符串为推荐频道
+func ParseFeedChannel(s string) (FeedChannel, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return FeedChannels[0], nil
+	}
+
+	for _, ch := range FeedChannels {
+		if strings.EqualFold(s, ch.Name) || s == ch.Label || s == ch.ID {
+			return ch, nil
+		}
+	}
+
+	names := make([]string, 0, len(FeedChannels))
+	for _, ch := range FeedChannels {
+		names = append(names, ch.Name)
+	}
+	return FeedChannel{}, fmt.Errorf("未知的频道: %s，可选 %s", s, strings.Join(names, "|"))
+}
+
+func (c FeedChannel) url() string {
+	if c.ID == FeedChannels[0].ID {
+		return "https://www.xiaohongshu.com/explore"
+	}
+	return "https://www.xiaohongshu.com/explore?channel_id=" + url.QueryEscape(c.ID)
+}
+
 type FeedsListAction struct {
 	page *rod.Page
 }
 
-func NewFeedsListAction(page *rod.Page) *FeedsListAction {
-	pp := page.Timeout(60 * time.Second)
+func NewFeedsListAction(page *rod.Page) (*FeedsListAction, error) {
+	pp := page.Timeout(configs.Get().Timeouts.PageAction.Duration)
+
+	if err := navigate(pp, "打开首页", "https://www.xiaohongshu.com"); err != nil {
+		return nil, err
+	}
+
+	return &FeedsListAction{page: pp}, nil
+}
+
+// NewChannelFeedsAction 打开发现页的指定频道。滚动加载耗时较长，超时时间比首页更宽松。
+func NewChannelFeedsAction(page *rod.Page, channel FeedChannel) (*FeedsListAction, error) {
+	pp := page.Timeout(configs.Get().Timeouts.ChannelFeeds.Duration)
 
-	pp.MustNavigate("https://www.xiaohongshu.com")
-	pp.MustWaitDOMStable()
+	if err := navigate(pp, "打开频道"+channel.Name, channel.url()); err != nil {
+		return nil, err
+	}
 
-	return &FeedsListAction{page: pp}
+	return &FeedsListAction{page: pp}, nil
 }
 
 // GetFeedsList 获取页面的 Feed 列表数据
@@ -29,7 +93,32 @@ func (f *FeedsListAction) GetFeedsList(ctx context.Context) ([]Feed, error) {
 
 	time.Sleep(1 * time.Second)
 
-	result := page.MustEval(`() => {
+	if err := checkCaptcha(page); err != nil {
+		return nil, err
+	}
+
+	return readHomeFeeds(page)
+}
+
+// GetFeedsPaged 滚动加载 limit 条不在 exclude 中的 Feed。
+// 首页是推荐流，每次打开的内容都不同，通过排除已返回的 ID 实现连续翻页。
+func (f *FeedsListAction) GetFeedsPaged(ctx context.Context, limit int, exclude []string) (*PagedFeeds, error) {
+	page := f.page.Context(ctx)
+
+	time.Sleep(1 * time.Second)
+
+	if err := checkCaptcha(page); err != nil {
+		return nil, err
+	}
+
+	return scrollCollectNewFeeds(page, limit, exclude, func() ([]Feed, error) {
+		return readHomeFeeds(page)
+	})
+}
+
+// readHomeFeeds 读取首页当前已加载的全部 Feed
+func readHomeFeeds(page *rod.Page) ([]Feed, error) {
+	result, err := evalJS(page, "读取首页 Feed 数据", `() => {
 		if (window.__INITIAL_STATE__ &&
 		    window.__INITIAL_STATE__.feed &&
 		    window.__INITIAL_STATE__.feed.feeds) {
@@ -40,14 +129,17 @@ func (f *FeedsListAction) GetFeedsList(ctx context.Context) ([]Feed, error) {
 			}
 		}
 		return "";
-	}`).String()
+	}`)
+	if err != nil {
+		return nil, err
+	}
 
-	if result == "" {
+	if result.String() == "" {
 		return nil, errors.ErrNoFeeds
 	}
 
 	var feeds []Feed
-	if err := json.Unmarshal([]byte(result), &feeds); err != nil {
+	if err := json.Unmarshal([]byte(result.String()), &feeds); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal feeds: %w", err)
 	}
 
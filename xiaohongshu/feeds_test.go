@@ -21,7 +21,8 @@ func TestGetFeedsList(t *testing.T) {
 	defer page.Close()
 
 	// NewFeedsListAction 内部已经处理导航
-	action := NewFeedsListAction(page)
+	action, err := NewFeedsListAction(page)
+	require.NoError(t, err)
 
 	feeds, err := action.GetFeedsList(context.Background())
 	require.NoError(t, err)
@@ -83,3 +84,20 @@ func TestGetFeedsList(t *testing.T) {
 		}
 	}
 }
+
+func TestParseFeedChannel(t *testing.T) {
+	ch, err := ParseFeedChannel("")
+	require.NoError(t, err)
+	require.Equal(t, "homefeed_recommend", ch.ID)
+
+	ch, err = ParseFeedChannel("Food")
+	require.NoError(t, err)
+	require.Equal(t, "homefeed.food_v3", ch.ID)
+
+	ch, err = ParseFeedChannel("穿搭")
+	require.NoError(t, err)
+	require.Equal(t, "fashion", ch.Name)
+
+	_, err = ParseFeedChannel("unknown")
+	require.Error(t, err)
+}
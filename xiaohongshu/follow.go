@@ -0,0 +1,152 @@
+package xiaohongshu
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
+)
+
+// ErrFollowUnconfirmed 已点击关注按钮，但无法读取点击后的关注状态
+var ErrFollowUnconfirmed = errors.New("已点击关注按钮，但未能确认关注状态，请到用户主页检查")
+
+// FollowResult 关注/取消关注的结果
+type FollowResult struct {
+	UserID   string `json:"user_id"`
+	Followed bool   `json:"followed"` // 操作后是否处于已关注状态
+	Changed  bool   `json:"changed"`  // 本次是否实际改变了关注状态
+}
+
+const (
+	// 取消关注时的二次确认按钮
+	unfollowConfirmText = "不再关注"
+)
+
+// followActionType 关注动作类型
+type followActionType string
+
+const (
+	actionFollow   followActionType = "关注"
+	actionUnfollow followActionType = "取消关注"
+)
+
+// FollowAction 负责处理关注相关交互
+type FollowAction struct {
+	page *rod.Page
+}
+
+func NewFollowAction(page *rod.Page) *FollowAction {
+	return &FollowAction{page: page}
+}
+
+// Follow 关注指定用户，如果已关注则直接返回
+func (a *FollowAction) Follow(ctx context.Context, userID, xsecToken string) (*FollowResult, error) {
+	return a.perform(ctx, userID, xsecToken, true)
+}
+
+// Unfollow 取消关注指定用户，如果未关注则直接返回
+func (a *FollowAction) Unfollow(ctx context.Context, userID, xsecToken string) (*FollowResult, error) {
+	return a.perform(ctx, userID, xsecToken, false)
+}
+
+func (a *FollowAction) perform(ctx context.Context, userID, xsecToken string, targetFollowed bool) (*FollowResult, error) {
+	actionType := actionFollow
+	if !targetFollowed {
+		actionType = actionUnfollow
+	}
+
+	page := a.page.Context(ctx).Timeout(configs.Get().Timeouts.PageAction.Duration)
+	url := makeUserProfileURL(userID, xsecToken)
+	logrus.Infof("Opening user profile page for %s: %s", actionType, url)
+
+	if err := navigate(page, "打开用户主页", url); err != nil {
+		return nil, err
+	}
+	sleepRange(delays().PageLoad)
+
+	followed, err := getFollowState(page)
+	if err != nil {
+		return nil, err
+	}
+
+	if followed == targetFollowed {
+		logrus.Infof("user %s 已处于%s状态，跳过点击", userID, actionType)
+		return &FollowResult{UserID: userID, Followed: followed, Changed: false}, nil
+	}
+
+	return a.toggleFollow(page, userID, targetFollowed, actionType)
+}
+
+func (a *FollowAction) toggleFollow(page *rod.Page, userID string, targetFollowed bool, actionType followActionType) (*FollowResult, error) {
+	for attempt := 1; attempt <= 2; attempt++ {
+		if err := clickFollowButton(page, targetFollowed); err != nil {
+			return nil, err
+		}
+		sleepRange(delays().ActionSettle)
+
+		followed, err := getFollowState(page)
+		if err != nil {
+			logrus.Warnf("验证%s状态失败: %v", actionType, err)
+			return nil, errors.Wrapf(ErrFollowUnconfirmed, "%s: %v", actionType, err)
+		}
+		if followed == targetFollowed {
+			logrus.Infof("user %s %s成功", userID, actionType)
+			return &FollowResult{UserID: userID, Followed: followed, Changed: true}, nil
+		}
+
+		logrus.Warnf("user %s %s可能未成功，状态未变化 (attempt %d)", userID, actionType, attempt)
+	}
+
+	return nil, errors.Errorf("%s失败: 点击后关注状态未变化", actionType)
+}
+
+// clickFollowButton 点击关注按钮，取消关注时处理二次确认弹窗
+func clickFollowButton(page *rod.Page, targetFollowed bool) error {
+	btn, err := page.Element(sel().UserProfile.FollowButton)
+	if err != nil {
+		return myerrors.SelectorNotFound("关注按钮", sel().UserProfile.FollowButton, err)
+	}
+	if err := btn.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return errors.Wrap(err, "点击关注按钮失败")
+	}
+
+	if !targetFollowed {
+		if confirm, err := page.Timeout(configs.Get().Timeouts.ElementProbe.Duration).ElementR(sel().UserProfile.UnfollowConfirm, "^"+unfollowConfirmText+"$"); err == nil {
+			if err := confirm.Click(proto.InputMouseButtonLeft, 1); err != nil {
+				return errors.Wrap(err, "确认取消关注失败")
+			}
+		}
+	}
+
+	return nil
+}
+
+// getFollowState 从关注按钮的文字判断是否已关注。
+// 自己的主页没有关注按钮，此时返回错误。
+func getFollowState(page *rod.Page) (bool, error) {
+	has, btn, err := page.Has(sel().UserProfile.FollowButton)
+	if err != nil {
+		return false, errors.Wrap(err, "查找关注按钮失败")
+	}
+	if !has {
+		return false, errors.New("没有找到关注按钮，可能是自己的主页或用户不存在")
+	}
+
+	text, err := btn.Text()
+	if err != nil {
+		return false, errors.Wrap(err, "读取关注按钮失败")
+	}
+
+	return isFollowedText(text), nil
+}
+
+// isFollowedText 已关注时按钮显示“已关注”或“互相关注”，未关注时显示“关注”或“回关”
+func isFollowedText(text string) bool {
+	text = strings.TrimSpace(text)
+	return strings.Contains(text, "已关注") || strings.Contains(text, "互相关注")
+}
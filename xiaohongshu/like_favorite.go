@@ -4,11 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"time"
 
 	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
 	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
 )
 
@@ -19,12 +20,6 @@ type ActionResult struct {
 	Message string `json:"message"`
 }
 
-// 选择器常量
-const (
-	SelectorLikeButton    = ".interact-container .left .like-lottie"
-	SelectorCollectButton = ".interact-container .left .reds-icon.collect-icon"
-)
-
 // interactActionType 交互动作类型
 type interactActionType string
 
@@ -43,21 +38,28 @@ func newInteractAction(page *rod.Page) *interactAction {
 	return &interactAction{page: page}
 }
 
-func (a *interactAction) preparePage(ctx context.Context, actionType interactActionType, feedID, xsecToken string) *rod.Page {
-	page := a.page.Context(ctx).Timeout(60 * time.Second)
+func (a *interactAction) preparePage(ctx context.Context, actionType interactActionType, feedID, xsecToken string) (*rod.Page, error) {
+	page := a.page.Context(ctx).Timeout(configs.Get().Timeouts.PageAction.Duration)
 	url := makeFeedDetailURL(feedID, xsecToken)
 	logrus.Infof("Opening feed detail page for %s: %s", actionType, url)
 
-	page.MustNavigate(url)
-	page.MustWaitDOMStable()
-	time.Sleep(1 * time.Second)
+	if err := navigate(page, "打开笔记详情页", url); err != nil {
+		return nil, err
+	}
+	sleepRange(delays().PageLoad)
 
-	return page
+	return page, nil
 }
 
-func (a *interactAction) performClick(page *rod.Page, selector string) {
-	element := page.MustElement(selector)
-	element.MustClick()
+func (a *interactAction) performClick(page *rod.Page, what, selector string) error {
+	element, err := findElement(page, what, selector)
+	if err != nil {
+		return err
+	}
+	if err := element.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return stepError(page, "点击"+what, selector, err)
+	}
+	return nil
 }
 
 // LikeAction 负责处理点赞相关交互
@@ -85,7 +87,10 @@ func (a *LikeAction) perform(ctx context.Context, feedID, xsecToken string, targ
 		actionType = actionUnlike
 	}
 
-	page := a.preparePage(ctx, actionType, feedID, xsecToken)
+	page, err := a.preparePage(ctx, actionType, feedID, xsecToken)
+	if err != nil {
+		return err
+	}
 
 	liked, _, err := a.getInteractState(page, feedID)
 	if err != nil {
@@ -106,8 +111,10 @@ func (a *LikeAction) perform(ctx context.Context, feedID, xsecToken string, targ
 }
 
 func (a *LikeAction) toggleLike(page *rod.Page, feedID string, targetLiked bool, actionType interactActionType) error {
-	a.performClick(page, SelectorLikeButton)
-	time.Sleep(3 * time.Second)
+	if err := a.performClick(page, "点赞按钮", sel().FeedDetail.LikeButton); err != nil {
+		return err
+	}
+	sleepRange(delays().ActionSettle)
 
 	liked, _, err := a.getInteractState(page, feedID)
 	if err != nil {
@@ -120,8 +127,10 @@ func (a *LikeAction) toggleLike(page *rod.Page, feedID string, targetLiked bool,
 	}
 
 	logrus.Warnf("feed %s %s可能未成功，状态未变化，尝试再次点击", feedID, actionType)
-	a.performClick(page, SelectorLikeButton)
-	time.Sleep(2 * time.Second)
+	if err := a.performClick(page, "点赞按钮", sel().FeedDetail.LikeButton); err != nil {
+		return err
+	}
+	sleepRange(delays().ActionSettle)
 
 	liked, _, err = a.getInteractState(page, feedID)
 	if err != nil {
@@ -161,7 +170,10 @@ func (a *FavoriteAction) perform(ctx context.Context, feedID, xsecToken string,
 		actionType = actionUnfavorite
 	}
 
-	page := a.preparePage(ctx, actionType, feedID, xsecToken)
+	page, err := a.preparePage(ctx, actionType, feedID, xsecToken)
+	if err != nil {
+		return err
+	}
 
 	_, collected, err := a.getInteractState(page, feedID)
 	if err != nil {
@@ -182,8 +194,10 @@ func (a *FavoriteAction) perform(ctx context.Context, feedID, xsecToken string,
 }
 
 func (a *FavoriteAction) toggleFavorite(page *rod.Page, feedID string, targetCollected bool, actionType interactActionType) error {
-	a.performClick(page, SelectorCollectButton)
-	time.Sleep(3 * time.Second)
+	if err := a.performClick(page, "收藏按钮", sel().FeedDetail.CollectButton); err != nil {
+		return err
+	}
+	sleepRange(delays().ActionSettle)
 
 	_, collected, err := a.getInteractState(page, feedID)
 	if err != nil {
@@ -196,8 +210,10 @@ func (a *FavoriteAction) toggleFavorite(page *rod.Page, feedID string, targetCol
 	}
 
 	logrus.Warnf("feed %s %s可能未成功，状态未变化，尝试再次点击", feedID, actionType)
-	a.performClick(page, SelectorCollectButton)
-	time.Sleep(2 * time.Second)
+	if err := a.performClick(page, "收藏按钮", sel().FeedDetail.CollectButton); err != nil {
+		return err
+	}
+	sleepRange(delays().ActionSettle)
 
 	_, collected, err = a.getInteractState(page, feedID)
 	if err != nil {
@@ -215,14 +231,18 @@ func (a *FavoriteAction) toggleFavorite(page *rod.Page, feedID string, targetCol
 // getInteractState 从 __INITIAL_STATE__ 读取笔记的点赞/收藏状态
 func (a *interactAction) getInteractState(page *rod.Page, feedID string) (liked bool, collected bool, err error) {
 
-	result := page.MustEval(`() => {
+	res, err := evalJS(page, "读取笔记互动状态", `() => {
 		if (window.__INITIAL_STATE__ &&
 		    window.__INITIAL_STATE__.note &&
 		    window.__INITIAL_STATE__.note.noteDetailMap) {
 			return JSON.stringify(window.__INITIAL_STATE__.note.noteDetailMap);
 		}
 		return "";
-	}`).String()
+	}`)
+	if err != nil {
+		return false, false, err
+	}
+	result := res.String()
 	if result == "" {
 		return false, false, myerrors.ErrNoFeedDetail
 	}
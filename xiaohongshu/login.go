@@ -19,14 +19,15 @@ func NewLogin(page *rod.Page) *LoginAction {
 
 func (a *LoginAction) CheckLoginStatus(ctx context.Context) (bool, error) {
 	pp := a.page.Context(ctx)
-	currentURL := pp.MustInfo().URL
-	if !strings.Contains(currentURL, "xiaohongshu.com/explore") {
-		pp.MustNavigate("https://www.xiaohongshu.com/explore").MustWaitLoad()
+	if !strings.Contains(pageURL(pp), "xiaohongshu.com/explore") {
+		if err := openExplorePage(pp); err != nil {
+			return false, err
+		}
 	}
 
 	time.Sleep(1 * time.Second)
 
-	exists, _, err := pp.Has(`.main-container .user .link-wrapper .channel`)
+	exists, _, err := pp.Has(sel().Login.UserChannel)
 	if err != nil {
 		return false, errors.Wrap(err, "check login status failed")
 	}
@@ -42,20 +43,24 @@ func (a *LoginAction) Login(ctx context.Context) error {
 	pp := a.page.Context(ctx)
 
 	// 导航到小红书首页，这会触发二维码弹窗
-	pp.MustNavigate("https://www.xiaohongshu.com/explore").MustWaitLoad()
+	if err := openExplorePage(pp); err != nil {
+		return err
+	}
 
 	// 等待一小段时间让页面完全加载
 	time.Sleep(2 * time.Second)
 
 	// 检查是否已经登录
-	if exists, _, _ := pp.Has(".main-container .user .link-wrapper .channel"); exists {
+	if exists, _, _ := pp.Has(sel().Login.UserChannel); exists {
 		// 已经登录，直接返回
 		return nil
 	}
 
 	// 等待扫码成功提示或者登录完成
 	// 这里我们等待登录成功的元素出现，这样更简单可靠
-	pp.MustElement(".main-container .user .link-wrapper .channel")
+	if _, err := pp.Element(sel().Login.UserChannel); err != nil {
+		return errors.Wrap(err, "等待扫码登录失败")
+	}
 
 	return nil
 }
@@ -64,18 +69,24 @@ func (a *LoginAction) FetchQrcodeImage(ctx context.Context) (string, bool, error
 	pp := a.page.Context(ctx)
 
 	// 导航到小红书首页，这会触发二维码弹窗
-	pp.MustNavigate("https://www.xiaohongshu.com/explore").MustWaitLoad()
+	if err := openExplorePage(pp); err != nil {
+		return "", false, err
+	}
 
 	// 等待一小段时间让页面完全加载
 	time.Sleep(2 * time.Second)
 
 	// 检查是否已经登录
-	if exists, _, _ := pp.Has(".main-container .user .link-wrapper .channel"); exists {
+	if exists, _, _ := pp.Has(sel().Login.UserChannel); exists {
 		return "", true, nil
 	}
 
 	// 获取二维码图片
-	src, err := pp.MustElement(".login-container .qrcode-img").Attribute("src")
+	qrcode, err := findElement(pp, "登录二维码", sel().Login.QRCode)
+	if err != nil {
+		return "", false, err
+	}
+	src, err := qrcode.Attribute("src")
 	if err != nil {
 		return "", false, errors.Wrap(err, "get qrcode src failed")
 	}
@@ -96,7 +107,7 @@ func (a *LoginAction) WaitForLogin(ctx context.Context) bool {
 		case <-ctx.Done():
 			return false
 		case <-ticker.C:
-			el, err := pp.Element(".main-container .user .link-wrapper .channel")
+			el, err := pp.Element(sel().Login.UserChannel)
 			if err == nil && el != nil {
 				return true
 			}
@@ -2,8 +2,10 @@ package xiaohongshu
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
 )
 
 type NavigateAction struct {
@@ -14,12 +16,28 @@ func NewNavigate(page *rod.Page) *NavigateAction {
 	return &NavigateAction{page: page}
 }
 
+const urlOfExplore = "https://www.xiaohongshu.com/explore"
+
+// openExplorePage 打开发现页并等待加载完成
+func openExplorePage(page *rod.Page) error {
+	if err := page.Navigate(urlOfExplore); err != nil {
+		return &StepError{Step: "打开发现页", URL: urlOfExplore, Err: err}
+	}
+	if err := page.WaitLoad(); err != nil {
+		return stepError(page, "等待发现页加载", "", err)
+	}
+	return nil
+}
+
 func (n *NavigateAction) ToExplorePage(ctx context.Context) error {
 	page := n.page.Context(ctx)
 
-	page.MustNavigate("https://www.xiaohongshu.com/explore").
-		MustWaitLoad().
-		MustElement(`div#app`)
+	if err := openExplorePage(page); err != nil {
+		return err
+	}
+	if _, err := findElement(page, "页面主体", sel().Explore.App); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -32,14 +50,24 @@ func (n *NavigateAction) ToProfilePage(ctx context.Context) error {
 		return err
 	}
 
-	page.MustWaitStable()
+	if err := page.WaitStable(time.Second); err != nil {
+		return stepError(page, "等待发现页稳定", "", err)
+	}
 
 	// Find and click the "我" channel link in sidebar
-	profileLink := page.MustElement(`div.main-container li.user.side-bar-component a.link-wrapper span.channel`)
-	profileLink.MustClick()
+	selectorProfileLink := sel().Explore.SidebarProfile
+	profileLink, err := findElement(page, "侧边栏个人主页入口", selectorProfileLink)
+	if err != nil {
+		return err
+	}
+	if err := profileLink.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return stepError(page, "点击侧边栏个人主页入口", selectorProfileLink, err)
+	}
 
 	// Wait for navigation to complete
-	page.MustWaitLoad()
+	if err := page.WaitLoad(); err != nil {
+		return stepError(page, "等待个人主页加载", "", err)
+	}
 
 	return nil
 }
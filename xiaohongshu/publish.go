@@ -13,6 +13,8 @@ import (
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
 )
 
 // PublishImageContent 发布图文内容
@@ -23,8 +25,35 @@ type PublishImageContent struct {
 	ImagePaths []string
 }
 
+// PublishStage 发布流程阶段
+type PublishStage string
+
+const (
+	PublishStageUploading  PublishStage = "uploading"
+	PublishStageFilling    PublishStage = "filling"
+	PublishStageSubmitting PublishStage = "submitting"
+)
+
 type PublishAction struct {
-	page *rod.Page
+	page       *rod.Page
+	onStage    func(PublishStage)
+	onProgress func(uploaded, total int)
+}
+
+// OnStage 设置发布阶段回调，用于上报发布进度
+func (p *PublishAction) OnStage(fn func(PublishStage)) {
+	p.onStage = fn
+}
+
+func (p *PublishAction) reportStage(stage PublishStage) {
+	if p.onStage != nil {
+		p.onStage(stage)
+	}
+}
+
+// OnProgress 设置图片上传进度回调，uploaded 为已上传的图片数，total 为图片总数
+func (p *PublishAction) OnProgress(fn func(uploaded, total int)) {
+	p.onProgress = fn
 }
 
 const (
@@ -33,11 +62,20 @@ const (
 
 func NewPublishImageAction(page *rod.Page) (*PublishAction, error) {
 
-	pp := page.Timeout(300 * time.Second)
+	pp := page.Timeout(configs.Get().Timeouts.Upload.Duration)
 
-	pp.MustNavigate(urlOfPublic).MustWaitIdle().MustWaitDOMStable()
+	if err := openPublishPage(pp); err != nil {
+		return nil, err
+	}
 	time.Sleep(1 * time.Second)
 
+	if err := checkCreatorLogin(pp); err != nil {
+		return nil, err
+	}
+	if err := checkCaptcha(pp); err != nil {
+		return nil, err
+	}
+
 	if err := mustClickPublishTab(pp, "上传图文"); err != nil {
 		logrus.Errorf("点击上传图文 TAB 失败: %v", err)
 		return nil, err
@@ -50,15 +88,60 @@ func NewPublishImageAction(page *rod.Page) (*PublishAction, error) {
 	}, nil
 }
 
-func (p *PublishAction) Publish(ctx context.Context, content PublishImageContent) error {
+// openPublishPage 打开创作中心发布页并等待加载完成
+func openPublishPage(page *rod.Page) error {
+	if err := page.Navigate(urlOfPublic); err != nil {
+		return &StepError{Step: "打开发布页", URL: urlOfPublic, Err: err}
+	}
+	if err := page.WaitIdle(time.Minute); err != nil {
+		return stepError(page, "等待发布页加载", "", err)
+	}
+	if err := page.WaitDOMStable(time.Second, 0); err != nil {
+		return stepError(page, "等待发布页加载", "", err)
+	}
+	return nil
+}
+
+// Publish 上传图片并提交，返回发布后的笔记信息
+func (p *PublishAction) Publish(ctx context.Context, content PublishImageContent) (*PublishResult, error) {
+	page, err := p.prepareImageNote(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := submitPublish(page, p.reportStage)
+	if err != nil {
+		return nil, errors.Wrap(err, "小红书发布失败")
+	}
+
+	lookupPublishedNote(ctx, page, result)
+	return result, nil
+}
+
+// SaveDraft 上传图片并填写内容后暂存为草稿，不发布
+func (p *PublishAction) SaveDraft(ctx context.Context, content PublishImageContent) error {
+	page, err := p.prepareImageNote(ctx, content)
+	if err != nil {
+		return err
+	}
+
+	if err := saveDraft(page); err != nil {
+		return errors.Wrap(err, "小红书保存草稿失败")
+	}
+	return nil
+}
+
+// prepareImageNote 上传图片并填写标题、正文和标签
+func (p *PublishAction) prepareImageNote(ctx context.Context, content PublishImageContent) (*rod.Page, error) {
 	if len(content.ImagePaths) == 0 {
-		return errors.New("图片不能为空")
+		return nil, errors.New("图片不能为空")
 	}
 
 	page := p.page.Context(ctx)
 
-	if err := uploadImages(page, content.ImagePaths); err != nil {
-		return errors.Wrap(err, "小红书上传图片失败")
+	p.reportStage(PublishStageUploading)
+	if err := uploadImages(page, content.ImagePaths, p.onProgress); err != nil {
+		return nil, errors.Wrap(err, "小红书上传图片失败")
 	}
 
 	tags := content.Tags
@@ -69,22 +152,25 @@ func (p *PublishAction) Publish(ctx context.Context, content PublishImageContent
 
 	logrus.Infof("发布内容: title=%s, images=%v, tags=%v", content.Title, len(content.ImagePaths), tags)
 
-	if err := submitPublish(page, content.Title, content.Content, tags); err != nil {
-		return errors.Wrap(err, "小红书发布失败")
+	p.reportStage(PublishStageFilling)
+	if err := fillImageNote(page, content.Title, content.Content, tags); err != nil {
+		return nil, errors.Wrap(err, "小红书填写内容失败")
 	}
 
-	return nil
+	return page, nil
 }
 
 func removePopCover(page *rod.Page) {
 
 	// 先移除弹窗封面
-	has, elem, err := page.Has("div.d-popover")
+	has, elem, err := page.Has(sel().Publish.Popover)
 	if err != nil {
 		return
 	}
 	if has {
-		elem.MustRemove()
+		if err := elem.Remove(); err != nil {
+			logrus.Debugf("移除弹窗失败: %v", err)
+		}
 	}
 
 	// 兜底：点击一下空位置吧
@@ -94,13 +180,25 @@ func removePopCover(page *rod.Page) {
 func clickEmptyPosition(page *rod.Page) {
 	x := 380 + rand.Intn(100)
 	y := 20 + rand.Intn(60)
-	page.Mouse.MustMoveTo(float64(x), float64(y)).MustClick(proto.InputMouseButtonLeft)
+	if err := page.Mouse.MoveTo(proto.NewPoint(float64(x), float64(y))); err != nil {
+		logrus.Debugf("移动鼠标失败: %v", err)
+		return
+	}
+	if err := page.Mouse.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		logrus.Debugf("点击空白位置失败: %v", err)
+	}
 }
 
 func mustClickPublishTab(page *rod.Page, tabname string) error {
-	page.MustElement(`div.upload-content`).MustWaitVisible()
+	uploadContent, err := findElement(page, "上传区域", sel().Publish.UploadContent)
+	if err != nil {
+		return err
+	}
+	if err := uploadContent.WaitVisible(); err != nil {
+		return stepError(page, "等待上传区域显示", sel().Publish.UploadContent, err)
+	}
 
-	deadline := time.Now().Add(15 * time.Second)
+	deadline := time.Now().Add(configs.Get().Timeouts.ElementWait.Duration)
 	for time.Now().Before(deadline) {
 		tab, blocked, err := getTabElement(page, tabname)
 		if err != nil {
@@ -130,11 +228,11 @@ func mustClickPublishTab(page *rod.Page, tabname string) error {
 		return nil
 	}
 
-	return errors.Errorf("没有找到发布 TAB - %s", tabname)
+	return myerrors.SelectorNotFound("发布 TAB - "+tabname, sel().Publish.Tab, nil)
 }
 
 func getTabElement(page *rod.Page, tabname string) (*rod.Element, bool, error) {
-	elems, err := page.Elements("div.creator-tab")
+	elems, err := page.Elements(sel().Publish.Tab)
 	if err != nil {
 		return nil, false, err
 	}
@@ -183,8 +281,8 @@ func isElementBlocked(elem *rod.Element) (bool, error) {
 	return result.Value.Bool(), nil
 }
 
-func uploadImages(page *rod.Page, imagesPaths []string) error {
-	pp := page.Timeout(30 * time.Second)
+func uploadImages(page *rod.Page, imagesPaths []string, onProgress func(uploaded, total int)) error {
+	pp := page.Timeout(configs.Get().Timeouts.ImageUpload.Duration)
 
 	// 验证文件路径有效性
 	validPaths := make([]string, 0, len(imagesPaths))
@@ -199,32 +297,42 @@ func uploadImages(page *rod.Page, imagesPaths []string) error {
 	}
 
 	// 等待上传输入框出现
-	uploadInput := pp.MustElement(".upload-input")
+	uploadInput, err := findElement(pp, "图片上传输入框", sel().Publish.UploadInput)
+	if err != nil {
+		return err
+	}
 
 	// 上传多个文件
-	uploadInput.MustSetFiles(validPaths...)
+	if err := uploadInput.SetFiles(validPaths); err != nil {
+		return stepError(pp, "选择上传图片", sel().Publish.UploadInput, err)
+	}
 
 	// 等待并验证上传完成
-	return waitForUploadComplete(pp, len(validPaths))
+	return waitForUploadComplete(pp, len(validPaths), onProgress)
 }
 
-// waitForUploadComplete 等待并验证上传完成
-func waitForUploadComplete(page *rod.Page, expectedCount int) error {
-	maxWaitTime := 60 * time.Second
+// waitForUploadComplete 等待并验证上传完成，已上传数量变化时通过 onProgress 上报
+func waitForUploadComplete(page *rod.Page, expectedCount int, onProgress func(uploaded, total int)) error {
+	maxWaitTime := configs.Get().Timeouts.ImageUpload.Duration
 	checkInterval := 500 * time.Millisecond
 	start := time.Now()
+	lastCount := -1
 
 	slog.Info("开始等待图片上传完成", "expected_count", expectedCount)
 
 	for time.Since(start) < maxWaitTime {
 		// 使用具体的pr类名检查已上传的图片
-		uploadedImages, err := page.Elements(".img-preview-area .pr")
+		uploadedImages, err := page.Elements(sel().Publish.UploadedImage)
 
 		slog.Info("uploadedImages", "uploadedImages", uploadedImages)
 
 		if err == nil {
 			currentCount := len(uploadedImages)
 			slog.Info("检测到已上传图片", "current_count", currentCount, "expected_count", expectedCount)
+			if onProgress != nil && currentCount > lastCount {
+				lastCount = currentCount
+				onProgress(min(currentCount, expectedCount), expectedCount)
+			}
 			if currentCount >= expectedCount {
 				slog.Info("所有图片上传完成", "count", currentCount)
 				return nil
@@ -236,13 +344,18 @@ func waitForUploadComplete(page *rod.Page, expectedCount int) error {
 		time.Sleep(checkInterval)
 	}
 
-	return errors.New("上传超时，请检查网络连接和图片大小")
+	return myerrors.New(myerrors.CodeUploadTimeout, "上传超时，请检查网络连接和图片大小")
 }
 
-func submitPublish(page *rod.Page, title, content string, tags []string) error {
-
-	titleElem := page.MustElement("div.d-input input")
-	titleElem.MustInput(title)
+// fillImageNote 填写图文笔记的标题、正文和标签，并检查长度限制
+func fillImageNote(page *rod.Page, title, content string, tags []string) error {
+	titleElem, err := findElement(page, "标题输入框", sel().Publish.TitleInput)
+	if err != nil {
+		return err
+	}
+	if err := titleElem.Input(title); err != nil {
+		return stepError(page, "输入标题", sel().Publish.TitleInput, err)
+	}
 
 	// 检查一下 title 的长度
 	time.Sleep(500 * time.Millisecond) // 等待页面渲染长度提示
@@ -253,13 +366,15 @@ func submitPublish(page *rod.Page, title, content string, tags []string) error {
 
 	time.Sleep(1 * time.Second)
 
-	if contentElem, ok := getContentElement(page); ok {
-		contentElem.MustInput(content)
-
-		inputTags(contentElem, tags)
-
-	} else {
-		return errors.New("没有找到内容输入框")
+	contentElem, err := getContentElement(page)
+	if err != nil {
+		return err
+	}
+	if err := contentElem.Input(content); err != nil {
+		return stepError(page, "输入正文", sel().Publish.ContentEditor, err)
+	}
+	if err := inputTags(contentElem, tags); err != nil {
+		return err
 	}
 
 	time.Sleep(1 * time.Second)
@@ -270,17 +385,27 @@ func submitPublish(page *rod.Page, title, content string, tags []string) error {
 	}
 	slog.Info("检查正文长度：通过")
 
-	submitButton := page.MustElement("div.submit div.d-button-content")
-	submitButton.MustClick()
+	return nil
+}
 
-	time.Sleep(3 * time.Second)
+// submitPublish 点击发布并确认发布结果
+func submitPublish(page *rod.Page, report func(PublishStage)) (*PublishResult, error) {
+	report(PublishStageSubmitting)
+	watcher := watchPublishResponse(page)
+	submitButton, err := findElement(page, "发布按钮", sel().Publish.Submit)
+	if err != nil {
+		return nil, err
+	}
+	if err := submitButton.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return nil, stepError(page, "点击发布按钮", sel().Publish.Submit, err)
+	}
 
-	return nil
+	return watcher.confirm()
 }
 
 // 检查标题是否超过最大长度
 func checkTitleMaxLength(page *rod.Page) error {
-	has, elem, err := page.Has(`div.title-container div.max_suffix`)
+	has, elem, err := page.Has(sel().Publish.TitleTooLong)
 	if err != nil {
 		return errors.Wrap(err, "检查标题长度元素失败")
 	}
@@ -296,11 +421,11 @@ func checkTitleMaxLength(page *rod.Page) error {
 		return errors.Wrap(err, "获取标题长度文本失败")
 	}
 
-	return makeMaxLengthError(titleLength)
+	return makeMaxLengthError(myerrors.CodeTitleTooLong, titleLength)
 }
 
 func checkContentMaxLength(page *rod.Page) error {
-	has, elem, err := page.Has(`div.edit-container div.length-error`)
+	has, elem, err := page.Has(sel().Publish.ContentTooLong)
 	if err != nil {
 		return errors.Wrap(err, "检查正文长度元素失败")
 	}
@@ -316,110 +441,137 @@ func checkContentMaxLength(page *rod.Page) error {
 		return errors.Wrap(err, "获取正文长度文本失败")
 	}
 
-	return makeMaxLengthError(contentLength)
+	return makeMaxLengthError(myerrors.CodeContentTooLong, contentLength)
 }
 
-func makeMaxLengthError(elemText string) error {
+func makeMaxLengthError(code myerrors.Code, elemText string) error {
 	parts := strings.Split(elemText, "/")
 	if len(parts) != 2 {
-		return errors.Errorf("长度超过限制: %s", elemText)
+		return myerrors.New(code, "长度超过限制: %s", elemText)
 	}
 
 	currLen, maxLen := parts[0], parts[1]
 
-	return errors.Errorf("当前输入长度为%s，最大长度为%s", currLen, maxLen)
+	return myerrors.New(code, "当前输入长度为%s，最大长度为%s", currLen, maxLen)
+}
+
+// checkCreatorLogin 未登录时创作中心会跳转到登录页
+func checkCreatorLogin(page *rod.Page) error {
+	info, err := page.Info()
+	if err != nil {
+		return errors.Wrap(err, "获取页面信息失败")
+	}
+	if strings.Contains(info.URL, "/login") {
+		return myerrors.ErrNotLoggedIn
+	}
+	return nil
 }
 
 // 查找内容输入框 - 使用Race方法处理两种样式
-func getContentElement(page *rod.Page) (*rod.Element, bool) {
-	var foundElement *rod.Element
-	var found bool
-
-	page.Race().
-		Element("div.ql-editor").MustHandle(func(e *rod.Element) {
-		foundElement = e
-		found = true
-	}).
+func getContentElement(page *rod.Page) (*rod.Element, error) {
+	elem, err := page.Race().
+		Element(sel().Publish.ContentEditor).
 		ElementFunc(func(page *rod.Page) (*rod.Element, error) {
 			return findTextboxByPlaceholder(page)
-		}).MustHandle(func(e *rod.Element) {
-		foundElement = e
-		found = true
-	}).
-		MustDo()
-
-	if found {
-		return foundElement, true
+		}).
+		Do()
+	if err != nil {
+		slog.Warn("no content element found by any method")
+		return nil, myerrors.SelectorNotFound("内容输入框", sel().Publish.ContentEditor, err)
 	}
 
-	slog.Warn("no content element found by any method")
-	return nil, false
+	return elem, nil
 }
 
-func inputTags(contentElem *rod.Element, tags []string) {
+func inputTags(contentElem *rod.Element, tags []string) error {
 	if len(tags) == 0 {
-		return
+		return nil
 	}
 
 	time.Sleep(1 * time.Second)
 
+	// 光标移到正文末尾，另起一段输入标签
 	for i := 0; i < 20; i++ {
-		contentElem.MustKeyActions().
-			Type(input.ArrowDown).
-			MustDo()
+		if err := typeKeys(contentElem, input.ArrowDown); err != nil {
+			return err
+		}
 		time.Sleep(10 * time.Millisecond)
 	}
 
-	contentElem.MustKeyActions().
-		Press(input.Enter).
-		Press(input.Enter).
-		MustDo()
+	if err := typeKeys(contentElem, input.Enter, input.Enter); err != nil {
+		return err
+	}
 
 	time.Sleep(1 * time.Second)
 
 	for _, tag := range tags {
 		tag = strings.TrimLeft(tag, "#")
-		inputTag(contentElem, tag)
+		if err := inputTag(contentElem, tag); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func inputTag(contentElem *rod.Element, tag string) {
-	contentElem.MustInput("#")
+func typeKeys(elem *rod.Element, keys ...input.Key) error {
+	actions, err := elem.KeyActions()
+	if err != nil {
+		return stepError(elem.Page(), "聚焦正文输入框", "", err)
+	}
+	if err := actions.Type(keys...).Do(); err != nil {
+		return stepError(elem.Page(), "在正文中按键", "", err)
+	}
+	return nil
+}
+
+func inputTag(contentElem *rod.Element, tag string) error {
+	if err := contentElem.Input("#"); err != nil {
+		return stepError(contentElem.Page(), "输入标签 "+tag, "", err)
+	}
 	time.Sleep(200 * time.Millisecond)
 
 	for _, char := range tag {
-		contentElem.MustInput(string(char))
+		if err := contentElem.Input(string(char)); err != nil {
+			return stepError(contentElem.Page(), "输入标签 "+tag, "", err)
+		}
 		time.Sleep(50 * time.Millisecond)
 	}
 
 	time.Sleep(1 * time.Second)
 
 	page := contentElem.Page()
-	topicContainer, err := page.Element("#creator-editor-topic-container")
+	topicContainer, err := page.Element(sel().Publish.TopicContainer)
 	if err == nil && topicContainer != nil {
-		firstItem, err := topicContainer.Element(".item")
+		firstItem, err := topicContainer.Element(sel().Publish.TopicItem)
 		if err == nil && firstItem != nil {
-			firstItem.MustClick()
+			if err := firstItem.Click(proto.InputMouseButtonLeft, 1); err != nil {
+				return stepError(page, "点击标签联想选项", sel().Publish.TopicContainer+" "+sel().Publish.TopicItem, err)
+			}
 			slog.Info("成功点击标签联想选项", "tag", tag)
 			time.Sleep(200 * time.Millisecond)
 		} else {
 			slog.Warn("未找到标签联想选项，直接输入空格", "tag", tag)
 			// 如果没有找到联想选项，输入空格结束
-			contentElem.MustInput(" ")
+			if err := contentElem.Input(" "); err != nil {
+				return stepError(page, "输入标签 "+tag, "", err)
+			}
 		}
 	} else {
 		slog.Warn("未找到标签联想下拉框，直接输入空格", "tag", tag)
 		// 如果没有找到下拉框，输入空格结束
-		contentElem.MustInput(" ")
+		if err := contentElem.Input(" "); err != nil {
+			return stepError(page, "输入标签 "+tag, "", err)
+		}
 	}
 
 	time.Sleep(500 * time.Millisecond) // 等待标签处理完成
+	return nil
 }
 
 func findTextboxByPlaceholder(page *rod.Page) (*rod.Element, error) {
-	elements := page.MustElements("p")
-	if elements == nil {
-		return nil, errors.New("no p elements found")
+	elements, err := page.Elements(sel().Publish.ContentHint)
+	if err != nil {
+		return nil, errors.Wrap(err, "no placeholder elements found")
 	}
 
 	// 查找包含指定placeholder的元素
@@ -0,0 +1,180 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// ErrPublishUnconfirmed 已点击发布但无法确认结果。此时笔记可能已经发出，不应自动重试。
+var ErrPublishUnconfirmed = errors.New("已提交发布，但未能确认发布结果，请到创作中心检查")
+
+// PublishResult 发布成功后的笔记信息
+type PublishResult struct {
+	NoteID    string `json:"note_id"`
+	XsecToken string `json:"xsec_token,omitempty"`
+	URL       string `json:"url,omitempty"`
+}
+
+const (
+	// 创作中心提交笔记的接口路径
+	publishNoteAPIPath = "/web_api/sns/v2/note"
+	// 发布成功后跳转的页面路径
+	publishSuccessPath = "/publish/success"
+)
+
+// publishWatcher 监听创作中心提交笔记的接口响应，需要在点击发布前创建
+type publishWatcher struct {
+	page      *rod.Page
+	requestID proto.NetworkRequestID
+	body      string
+	wait      func()
+}
+
+func watchPublishResponse(page *rod.Page) *publishWatcher {
+	w := &publishWatcher{page: page}
+
+	pp := page.Timeout(configs.Get().Timeouts.PublishConfirm.Duration)
+	w.wait = pp.EachEvent(func(e *proto.NetworkResponseReceived) {
+		if strings.Contains(e.Response.URL, publishNoteAPIPath) {
+			w.requestID = e.RequestID
+		}
+	}, func(e *proto.NetworkLoadingFinished) bool {
+		if w.requestID == "" || e.RequestID != w.requestID {
+			return false
+		}
+
+		// 监听结束后 Network 域会被恢复为关闭状态，需要在此时读取响应体
+		body, err := proto.NetworkGetResponseBody{RequestID: w.requestID}.Call(page)
+		if err != nil {
+			logrus.Warnf("读取发布接口响应失败: %v", err)
+		} else {
+			w.body = body.Body
+		}
+		return true
+	})
+
+	return w
+}
+
+// confirm 等待发布接口返回并解析笔记ID。
+// 未捕获到接口响应时，退回到检查发布成功页面。
+func (w *publishWatcher) confirm() (*PublishResult, error) {
+	w.wait()
+
+	if w.body != "" {
+		noteID, err := parsePublishResponse(w.body)
+		if err != nil {
+			return nil, err
+		}
+		if noteID != "" {
+			logrus.Infof("发布成功: note_id=%s", noteID)
+			return &PublishResult{NoteID: noteID}, nil
+		}
+	}
+
+	// 兜底：检查是否跳转到发布成功页面
+	info, err := w.page.Info()
+	if err == nil && strings.Contains(info.URL, publishSuccessPath) {
+		noteID := ""
+		if u, err := url.Parse(info.URL); err == nil {
+			noteID = u.Query().Get("noteId")
+		}
+		logrus.Infof("发布成功（成功页面）: note_id=%s", noteID)
+		return &PublishResult{NoteID: noteID}, nil
+	}
+
+	return nil, ErrPublishUnconfirmed
+}
+
+// parsePublishResponse 解析发布接口的响应体，接口明确返回失败时返回错误
+func parsePublishResponse(body string) (string, error) {
+	var resp struct {
+		Success bool   `json:"success"`
+		Code    int    `json:"code"`
+		Msg     string `json:"msg"`
+		Data    struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		logrus.Warnf("解析发布接口响应失败: %v", err)
+		return "", nil
+	}
+
+	if !resp.Success {
+		return "", errors.Errorf("发布被拒绝: code=%d, msg=%s", resp.Code, resp.Msg)
+	}
+
+	return resp.Data.ID, nil
+}
+
+// lookupPublishedNote 在自己的主页中查找刚发布的笔记，补全 xsec_token 和链接。
+// 笔记已经发出，这里的失败只记录日志，不影响发布结果。
+// 没有笔记ID时不查找，避免把同名的旧笔记当作新笔记。
+func lookupPublishedNote(ctx context.Context, page *rod.Page, result *PublishResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Warnf("查找已发布笔记失败: %v", r)
+		}
+		result.URL = makePublishedNoteURL(result.NoteID, result.XsecToken)
+	}()
+
+	if result.NoteID == "" {
+		logrus.Warnf("未获取到笔记ID，跳过查找已发布笔记")
+		return
+	}
+
+	// 新笔记可能稍后才出现在主页
+	const attempts = 3
+	for i := 0; i < attempts; i++ {
+		profile, err := NewUserProfileAction(page).GetMyProfileViaSidebar(ctx)
+		if err != nil {
+			logrus.Warnf("获取个人主页失败: %v", err)
+		} else if feed, ok := findPublishedFeed(profile.Feeds, result.NoteID); ok {
+			result.XsecToken = feed.XsecToken
+			return
+		}
+
+		if i < attempts-1 {
+			select {
+			case <-ctx.Done():
+				logrus.Warnf("查找已发布笔记被取消: %v", ctx.Err())
+				return
+			case <-time.After(3 * time.Second):
+			}
+		}
+	}
+
+	logrus.Warnf("个人主页中暂未找到刚发布的笔记: note_id=%s", result.NoteID)
+}
+
+// findPublishedFeed 按笔记ID查找
+func findPublishedFeed(feeds []Feed, noteID string) (Feed, bool) {
+	for _, feed := range feeds {
+		if feed.ID == noteID {
+			return feed, true
+		}
+	}
+
+	return Feed{}, false
+}
+
+func makePublishedNoteURL(noteID, xsecToken string) string {
+	if noteID == "" {
+		return ""
+	}
+	if xsecToken == "" {
+		return fmt.Sprintf("https://www.xiaohongshu.com/explore/%s", noteID)
+	}
+	return fmt.Sprintf("https://www.xiaohongshu.com/explore/%s?xsec_token=%s&xsec_source=pc_feed", noteID, xsecToken)
+}
@@ -0,0 +1,42 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePublishResponse(t *testing.T) {
+	noteID, err := parsePublishResponse(`{"success":true,"code":0,"msg":"成功","data":{"id":"6650a1b2c3d4e5f6a7b8c9d0","score":10}}`)
+	require.NoError(t, err)
+	require.Equal(t, "6650a1b2c3d4e5f6a7b8c9d0", noteID)
+
+	_, err = parsePublishResponse(`{"success":false,"code":-9131,"msg":"内容违规"}`)
+	require.ErrorContains(t, err, "内容违规")
+
+	// 无法解析时交给成功页面兜底判断
+	noteID, err = parsePublishResponse(`<html></html>`)
+	require.NoError(t, err)
+	require.Empty(t, noteID)
+}
+
+func TestFindPublishedFeed(t *testing.T) {
+	feeds := []Feed{
+		{ID: "n2", XsecToken: "t2", NoteCard: NoteCard{DisplayTitle: "新笔记"}},
+		{ID: "n1", XsecToken: "t1", NoteCard: NoteCard{DisplayTitle: "旧笔记"}},
+	}
+
+	feed, ok := findPublishedFeed(feeds, "n1")
+	require.True(t, ok)
+	require.Equal(t, "t1", feed.XsecToken)
+
+	// 不按标题匹配，避免返回同名的旧笔记
+	_, ok = findPublishedFeed(feeds, "")
+	require.False(t, ok)
+
+	_, ok = findPublishedFeed(feeds, "n3")
+	require.False(t, ok)
+
+	require.Equal(t, "https://www.xiaohongshu.com/explore/n1?xsec_token=t1&xsec_source=pc_feed", makePublishedNoteURL("n1", "t1"))
+	require.Equal(t, "https://www.xiaohongshu.com/explore/n1", makePublishedNoteURL("n1", ""))
+}
@@ -2,9 +2,11 @@ package xiaohongshu
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/xpzouying/xiaohongshu-mcp/browser"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -23,10 +25,19 @@ func TestPublish(t *testing.T) {
 	action, err := NewPublishImageAction(page)
 	require.NoError(t, err)
 
-	err = action.Publish(context.Background(), PublishImageContent{
+	_, err = action.Publish(context.Background(), PublishImageContent{
 		Title:      "Hello World",
 		Content:    "Hello World",
 		ImagePaths: []string{"/tmp/1.jpg"},
 	})
 	assert.NoError(t, err)
 }
+
+func TestMakeMaxLengthError(t *testing.T) {
+	err := makeMaxLengthError(myerrors.CodeTitleTooLong, "45/40")
+	require.True(t, errors.Is(err, myerrors.ErrTitleTooLong))
+	require.Equal(t, "当前输入长度为45，最大长度为40", err.Error())
+
+	err = makeMaxLengthError(myerrors.CodeContentTooLong, "超出")
+	require.Equal(t, myerrors.CodeContentTooLong, myerrors.CodeOf(err))
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
 )
 
 // PublishVideoContent 发布视频内容
@@ -22,11 +24,20 @@ type PublishVideoContent struct {
 
 // NewPublishVideoAction 进入发布页并切换到“上传视频”
 func NewPublishVideoAction(page *rod.Page) (*PublishAction, error) {
-	pp := page.Timeout(300 * time.Second)
+	pp := page.Timeout(configs.Get().Timeouts.Upload.Duration)
 
-	pp.MustNavigate(urlOfPublic).MustWaitIdle().MustWaitDOMStable()
+	if err := openPublishPage(pp); err != nil {
+		return nil, err
+	}
 	time.Sleep(1 * time.Second)
 
+	if err := checkCreatorLogin(pp); err != nil {
+		return nil, err
+	}
+	if err := checkCaptcha(pp); err != nil {
+		return nil, err
+	}
+
 	if err := mustClickPublishTab(page, "上传视频"); err != nil {
 		return nil, errors.Wrap(err, "切换到上传视频失败")
 	}
@@ -36,27 +47,59 @@ func NewPublishVideoAction(page *rod.Page) (*PublishAction, error) {
 	return &PublishAction{page: pp}, nil
 }
 
-// PublishVideo 上传视频并提交
-func (p *PublishAction) PublishVideo(ctx context.Context, content PublishVideoContent) error {
+// PublishVideo 上传视频并提交，返回发布后的笔记信息
+func (p *PublishAction) PublishVideo(ctx context.Context, content PublishVideoContent) (*PublishResult, error) {
+	page, err := p.prepareVideoNote(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := submitPublishVideo(page, p.reportStage)
+	if err != nil {
+		return nil, errors.Wrap(err, "小红书发布失败")
+	}
+
+	lookupPublishedNote(ctx, page, result)
+	return result, nil
+}
+
+// SaveVideoDraft 上传视频并填写内容后暂存为草稿，不发布
+func (p *PublishAction) SaveVideoDraft(ctx context.Context, content PublishVideoContent) error {
+	page, err := p.prepareVideoNote(ctx, content)
+	if err != nil {
+		return err
+	}
+
+	if err := saveDraft(page); err != nil {
+		return errors.Wrap(err, "小红书保存草稿失败")
+	}
+	return nil
+}
+
+// prepareVideoNote 上传视频并填写标题、正文和标签
+func (p *PublishAction) prepareVideoNote(ctx context.Context, content PublishVideoContent) (*rod.Page, error) {
 	if content.VideoPath == "" {
-		return errors.New("视频不能为空")
+		return nil, errors.New("视频不能为空")
 	}
 
 	page := p.page.Context(ctx)
 
+	p.reportStage(PublishStageUploading)
 	if err := uploadVideo(page, content.VideoPath); err != nil {
-		return errors.Wrap(err, "小红书上传视频失败")
+		return nil, errors.Wrap(err, "小红书上传视频失败")
 	}
 
-	if err := submitPublishVideo(page, content.Title, content.Content, content.Tags); err != nil {
-		return errors.Wrap(err, "小红书发布失败")
+	p.reportStage(PublishStageFilling)
+	if err := fillVideoNote(page, content.Title, content.Content, content.Tags); err != nil {
+		return nil, errors.Wrap(err, "小红书填写内容失败")
 	}
-	return nil
+
+	return page, nil
 }
 
 // uploadVideo 上传单个本地视频
 func uploadVideo(page *rod.Page, videoPath string) error {
-	pp := page.Timeout(5 * time.Minute) // 视频处理耗时更长
+	pp := page.Timeout(configs.Get().Timeouts.VideoProcess.Duration) // 视频处理耗时更长
 
 	if _, err := os.Stat(videoPath); os.IsNotExist(err) {
 		return errors.Wrapf(err, "视频文件不存在: %s", videoPath)
@@ -65,15 +108,17 @@ func uploadVideo(page *rod.Page, videoPath string) error {
 	// 寻找文件上传输入框（与图文一致的 class，或退回到 input[type=file]）
 	var fileInput *rod.Element
 	var err error
-	fileInput, err = pp.Element(".upload-input")
+	fileInput, err = pp.Element(sel().Publish.UploadInput)
 	if err != nil || fileInput == nil {
-		fileInput, err = pp.Element("input[type='file']")
+		fileInput, err = pp.Element(sel().Publish.FileInput)
 		if err != nil || fileInput == nil {
-			return errors.New("未找到视频上传输入框")
+			return myerrors.SelectorNotFound("视频上传输入框", sel().Publish.UploadInput+", "+sel().Publish.FileInput, err)
 		}
 	}
 
-	fileInput.MustSetFiles(videoPath)
+	if err := fileInput.SetFiles([]string{videoPath}); err != nil {
+		return stepError(pp, "选择上传视频", sel().Publish.UploadInput, err)
+	}
 
 	// 对于视频，等待发布按钮变为可点击即表示处理完成
 	btn, err := waitForPublishButtonClickable(pp)
@@ -86,10 +131,10 @@ func uploadVideo(page *rod.Page, videoPath string) error {
 
 // waitForPublishButtonClickable 等待发布按钮可点击
 func waitForPublishButtonClickable(page *rod.Page) (*rod.Element, error) {
-	maxWait := 10 * time.Minute
+	maxWait := configs.Get().Timeouts.VideoProcess.Duration
 	interval := 1 * time.Second
 	start := time.Now()
-	selector := "button.publishBtn"
+	selector := sel().Publish.VideoSubmit
 
 	slog.Info("开始等待发布按钮可点击(视频)")
 
@@ -112,37 +157,51 @@ func waitForPublishButtonClickable(page *rod.Page) (*rod.Element, error) {
 		}
 		time.Sleep(interval)
 	}
-	return nil, errors.New("等待发布按钮可点击超时")
+	return nil, myerrors.New(myerrors.CodeUploadTimeout, "等待发布按钮可点击超时，视频可能仍在上传或处理中")
 }
 
-// submitPublishVideo 填写标题、正文、标签并点击发布（等待按钮可点击后再提交）
-func submitPublishVideo(page *rod.Page, title, content string, tags []string) error {
+// fillVideoNote 填写视频笔记的标题、正文和标签
+func fillVideoNote(page *rod.Page, title, content string, tags []string) error {
 	// 标题
-	titleElem := page.MustElement("div.d-input input")
-	titleElem.MustInput(title)
+	titleElem, err := findElement(page, "标题输入框", sel().Publish.TitleInput)
+	if err != nil {
+		return err
+	}
+	if err := titleElem.Input(title); err != nil {
+		return stepError(page, "输入标题", sel().Publish.TitleInput, err)
+	}
 	time.Sleep(1 * time.Second)
 
 	// 正文 + 标签
-	if contentElem, ok := getContentElement(page); ok {
-		contentElem.MustInput(content)
-		inputTags(contentElem, tags)
-	} else {
-		return errors.New("没有找到内容输入框")
+	contentElem, err := getContentElement(page)
+	if err != nil {
+		return err
+	}
+	if err := contentElem.Input(content); err != nil {
+		return stepError(page, "输入正文", sel().Publish.ContentEditor, err)
+	}
+	if err := inputTags(contentElem, tags); err != nil {
+		return err
 	}
 
 	time.Sleep(1 * time.Second)
+	return nil
+}
 
+// submitPublishVideo 等待发布按钮可点击后提交，并确认发布结果
+func submitPublishVideo(page *rod.Page, report func(PublishStage)) (*PublishResult, error) {
 	// 等待发布按钮可点击
 	btn, err := waitForPublishButtonClickable(page)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// 点击发布
+	report(PublishStageSubmitting)
+	watcher := watchPublishResponse(page)
 	if err := btn.Click(proto.InputMouseButtonLeft, 1); err != nil {
-		return errors.Wrap(err, "点击发布按钮失败")
+		return nil, errors.Wrap(err, "点击发布按钮失败")
 	}
 
-	time.Sleep(3 * time.Second)
-	return nil
+	return watcher.confirm()
 }
@@ -0,0 +1,181 @@
+package xiaohongshu
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// MaxPagedResults 单次分页请求最多加载的结果数（page * limit）
+	MaxPagedResults = 1000
+
+	// 连续多少次滚动没有新内容时认为已经到底
+	maxStalledScrolls = 3
+)
+
+// PageOptions 滚动分页选项。Limit 为 0 时不滚动，只返回首屏内容。
+type PageOptions struct {
+	Limit int // 每页数量
+	Page  int // 页码，从 1 开始，默认 1
+}
+
+// Paged 是否需要滚动分页
+func (o PageOptions) Paged() bool {
+	return o.Limit > 0
+}
+
+// Validate 校验分页参数，并填充默认页码
+func (o *PageOptions) Validate() error {
+	if o.Limit < 0 {
+		return fmt.Errorf("limit 不能为负数")
+	}
+	if o.Page < 0 {
+		return fmt.Errorf("page 不能为负数")
+	}
+	if o.Page == 0 {
+		o.Page = 1
+	}
+	if o.Limit > 0 && o.Limit*o.Page > MaxPagedResults {
+		return fmt.Errorf("page * limit 不能超过 %d", MaxPagedResults)
+	}
+	return nil
+}
+
+// PagedFeeds 一页结果
+type PagedFeeds struct {
+	Feeds   []Feed
+	HasMore bool // 是否可能还有下一页
+}
+
+// feedCollector 按 ID 去重合并多次加载的 Feed，保持首次出现的顺序
+type feedCollector struct {
+	feeds []Feed
+	seen  map[string]bool
+}
+
+// newFeedCollector 创建收集器，exclude 中的 ID 视为已出现，不会再被收集
+func newFeedCollector(exclude ...string) *feedCollector {
+	seen := make(map[string]bool, len(exclude))
+	for _, id := range exclude {
+		seen[id] = true
+	}
+	return &feedCollector{seen: seen}
+}
+
+// add 合并一批结果，返回新增数量
+func (c *feedCollector) add(batch []Feed) int {
+	added := 0
+	for _, feed := range batch {
+		if feed.ID == "" || c.seen[feed.ID] {
+			continue
+		}
+		c.seen[feed.ID] = true
+		c.feeds = append(c.feeds, feed)
+		added++
+	}
+	return added
+}
+
+// page 截取第 opts.Page 页
+func (c *feedCollector) page(opts PageOptions, exhausted bool) *PagedFeeds {
+	start := (opts.Page - 1) * opts.Limit
+	end := start + opts.Limit
+
+	if start >= len(c.feeds) {
+		return &PagedFeeds{Feeds: []Feed{}, HasMore: false}
+	}
+	if end > len(c.feeds) {
+		end = len(c.feeds)
+	}
+
+	return &PagedFeeds{
+		Feeds:   c.feeds[start:end],
+		HasMore: end < len(c.feeds) || !exhausted,
+	}
+}
+
+// scrollCollectFeeds 不断滚动页面加载更多内容，直到凑够 opts 指定页或不再有新内容。
+// read 每次读取页面当前已加载的全部 Feed。
+func scrollCollectFeeds(page *rod.Page, opts PageOptions, read func() ([]Feed, error)) (*PagedFeeds, error) {
+	collector := newFeedCollector()
+
+	exhausted, err := scrollCollect(page, collector, opts.Page*opts.Limit, read)
+	if err != nil {
+		return nil, err
+	}
+
+	return collector.page(opts, exhausted), nil
+}
+
+// firstScreenFeeds 返回首屏内容，并滚动确认之后是否还有更多内容
+func firstScreenFeeds(page *rod.Page, read func() ([]Feed, error)) (*PagedFeeds, error) {
+	feeds, err := read()
+	if err != nil {
+		return nil, err
+	}
+
+	collector := newFeedCollector()
+	n := collector.add(feeds)
+	if n == 0 {
+		return &PagedFeeds{Feeds: []Feed{}, HasMore: false}, nil
+	}
+
+	exhausted, err := scrollCollect(page, collector, n+1, read)
+	if err != nil {
+		return nil, err
+	}
+
+	return collector.page(PageOptions{Limit: n, Page: 1}, exhausted), nil
+}
+
+// scrollCollectNewFeeds 滚动加载 limit 条不在 exclude 中的 Feed，用于基于游标的连续翻页
+func scrollCollectNewFeeds(page *rod.Page, limit int, exclude []string, read func() ([]Feed, error)) (*PagedFeeds, error) {
+	collector := newFeedCollector(exclude...)
+
+	exhausted, err := scrollCollect(page, collector, limit, read)
+	if err != nil {
+		return nil, err
+	}
+
+	return collector.page(PageOptions{Limit: limit, Page: 1}, exhausted), nil
+}
+
+// scrollCollect 滚动直到收集器中有 want 条内容，返回是否已经没有更多内容
+func scrollCollect(page *rod.Page, collector *feedCollector, want int, read func() ([]Feed, error)) (bool, error) {
+	feeds, err := read()
+	if err != nil {
+		return false, err
+	}
+	collector.add(feeds)
+
+	stalled := 0
+	for len(collector.feeds) < want && stalled < maxStalledScrolls {
+		if _, err := page.Eval(`() => window.scrollTo(0, document.documentElement.scrollHeight)`); err != nil {
+			return false, fmt.Errorf("滚动页面失败: %w", err)
+		}
+		time.Sleep(time.Duration(1200+rand.Intn(800)) * time.Millisecond)
+
+		feeds, err := read()
+		if err != nil {
+			return false, err
+		}
+
+		if collector.add(feeds) == 0 {
+			stalled++
+		} else {
+			stalled = 0
+		}
+		logrus.Debugf("滚动加载: 已获取 %d/%d", len(collector.feeds), want)
+	}
+
+	exhausted := len(collector.feeds) < want
+	if exhausted {
+		logrus.Infof("已加载全部内容: 共 %d 条", len(collector.feeds))
+	}
+
+	return exhausted, nil
+}
@@ -0,0 +1,58 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func feedsWithIDs(ids ...string) []Feed {
+	feeds := make([]Feed, 0, len(ids))
+	for _, id := range ids {
+		feeds = append(feeds, Feed{ID: id})
+	}
+	return feeds
+}
+
+func TestFeedCollector(t *testing.T) {
+	c := newFeedCollector()
+
+	require.Equal(t, 3, c.add(feedsWithIDs("a", "b", "c")))
+	// 滚动后页面返回的是累计结果，重复的不再计入
+	require.Equal(t, 2, c.add(feedsWithIDs("a", "b", "c", "d", "", "e")))
+	require.Equal(t, 0, c.add(feedsWithIDs("e", "d")))
+
+	page := c.page(PageOptions{Limit: 2, Page: 2}, false)
+	require.Equal(t, feedsWithIDs("c", "d"), page.Feeds)
+	require.True(t, page.HasMore)
+
+	page = c.page(PageOptions{Limit: 2, Page: 3}, true)
+	require.Equal(t, feedsWithIDs("e"), page.Feeds)
+	require.False(t, page.HasMore)
+
+	page = c.page(PageOptions{Limit: 2, Page: 4}, true)
+	require.Empty(t, page.Feeds)
+	require.False(t, page.HasMore)
+}
+
+func TestFeedCollectorExclude(t *testing.T) {
+	c := newFeedCollector("a", "b")
+
+	require.Equal(t, 2, c.add(feedsWithIDs("a", "b", "c", "d")))
+	require.Equal(t, feedsWithIDs("c", "d"), c.feeds)
+}
+
+func TestPageOptionsValidate(t *testing.T) {
+	opts := PageOptions{Limit: 50}
+	require.NoError(t, opts.Validate())
+	require.Equal(t, 1, opts.Page)
+	require.True(t, opts.Paged())
+
+	require.False(t, (PageOptions{}).Paged())
+
+	opts = PageOptions{Limit: 200, Page: 6}
+	require.Error(t, opts.Validate())
+
+	opts = PageOptions{Limit: -1}
+	require.Error(t, opts.Validate())
+}
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
 	"github.com/xpzouying/xiaohongshu-mcp/errors"
 )
 
@@ -160,19 +162,52 @@ type SearchAction struct {
 }
 
 func NewSearchAction(page *rod.Page) *SearchAction {
-	pp := page.Timeout(60 * time.Second)
+	pp := page.Timeout(configs.Get().Timeouts.PageAction.Duration)
 
 	return &SearchAction{page: pp}
 }
 
+// Search 搜索并返回首屏结果
 func (s *SearchAction) Search(ctx context.Context, keyword string, filters ...FilterOption) ([]Feed, error) {
 	page := s.page.Context(ctx)
 
+	if err := openSearchPage(page, keyword, filters...); err != nil {
+		return nil, err
+	}
+
+	return readSearchFeeds(page)
+}
+
+// SearchPaged 搜索并滚动加载，返回 opts 指定的一页结果
+func (s *SearchAction) SearchPaged(ctx context.Context, keyword string, opts PageOptions, filters ...FilterOption) (*PagedFeeds, error) {
+	page := s.page.Context(ctx)
+
+	if err := openSearchPage(page, keyword, filters...); err != nil {
+		return nil, err
+	}
+
+	return scrollCollectFeeds(page, opts, func() ([]Feed, error) {
+		return readSearchFeeds(page)
+	})
+}
+
+// openSearchPage 打开搜索结果页并应用筛选条件
+func openSearchPage(page *rod.Page, keyword string, filters ...FilterOption) error {
 	searchURL := makeSearchURL(keyword)
-	page.MustNavigate(searchURL)
-	page.MustWaitStable()
+	if err := page.Navigate(searchURL); err != nil {
+		return &StepError{Step: "打开搜索页", URL: searchURL, Err: err}
+	}
+	if err := page.WaitStable(time.Second); err != nil {
+		return stepError(page, "等待搜索页加载", "", err)
+	}
+
+	if err := checkCaptcha(page); err != nil {
+		return err
+	}
 
-	page.MustWait(`() => window.__INITIAL_STATE__ !== undefined`)
+	if err := waitInitialState(page, "等待搜索结果数据"); err != nil {
+		return err
+	}
 
 	// 如果有筛选条件，则应用筛选
 	if len(filters) > 0 {
@@ -181,7 +216,7 @@ func (s *SearchAction) Search(ctx context.Context, keyword string, filters ...Fi
 		for _, filter := range filters {
 			internalFilters, err := convertToInternalFilters(filter)
 			if err != nil {
-				return nil, fmt.Errorf("筛选选项转换失败: %w", err)
+				return fmt.Errorf("筛选选项转换失败: %w", err)
 			}
 			allInternalFilters = append(allInternalFilters, internalFilters...)
 		}
@@ -189,32 +224,52 @@ func (s *SearchAction) Search(ctx context.Context, keyword string, filters ...Fi
 		// 验证所有内部筛选选项
 		for _, filter := range allInternalFilters {
 			if err := validateInternalFilterOption(filter); err != nil {
-				return nil, fmt.Errorf("筛选选项验证失败: %w", err)
+				return fmt.Errorf("筛选选项验证失败: %w", err)
 			}
 		}
 
 		// 悬停在筛选按钮上
-		filterButton := page.MustElement(`div.filter`)
-		filterButton.MustHover()
+		filterButton, err := findElement(page, "筛选按钮", sel().Search.FilterButton)
+		if err != nil {
+			return err
+		}
+		if err := filterButton.Hover(); err != nil {
+			return stepError(page, "悬停筛选按钮", sel().Search.FilterButton, err)
+		}
 
 		// 等待筛选面板出现
-		page.MustWait(`() => document.querySelector('div.filter-panel') !== null`)
+		if _, err := findElement(page, "筛选面板", sel().Search.FilterPanel); err != nil {
+			return err
+		}
 
 		// 应用所有筛选条件
 		for _, filter := range allInternalFilters {
-			selector := fmt.Sprintf(`div.filter-panel div.filters:nth-child(%d) div.tags:nth-child(%d)`,
-				filter.FiltersIndex, filter.TagsIndex)
-			option := page.MustElement(selector)
-			option.MustClick()
+			selector := fmt.Sprintf(sel().Search.FilterOption, filter.FiltersIndex, filter.TagsIndex)
+			option, err := findElement(page, "筛选选项", selector)
+			if err != nil {
+				return err
+			}
+			if err := option.Click(proto.InputMouseButtonLeft, 1); err != nil {
+				return stepError(page, "点击筛选选项", selector, err)
+			}
 		}
 
 		// 等待页面更新
-		page.MustWaitStable()
+		if err := page.WaitStable(time.Second); err != nil {
+			return stepError(page, "等待筛选结果加载", "", err)
+		}
 		// 重新等待 __INITIAL_STATE__ 更新
-		page.MustWait(`() => window.__INITIAL_STATE__ !== undefined`)
+		if err := waitInitialState(page, "等待筛选结果数据"); err != nil {
+			return err
+		}
 	}
 
-	result := page.MustEval(`() => {
+	return nil
+}
+
+// readSearchFeeds 读取 __INITIAL_STATE__ 中已加载的搜索结果
+func readSearchFeeds(page *rod.Page) ([]Feed, error) {
+	result, err := evalJS(page, "读取搜索结果数据", `() => {
 		if (window.__INITIAL_STATE__ &&
 		    window.__INITIAL_STATE__.search &&
 		    window.__INITIAL_STATE__.search.feeds) {
@@ -225,14 +280,17 @@ func (s *SearchAction) Search(ctx context.Context, keyword string, filters ...Fi
 			}
 		}
 		return "";
-	}`).String()
+	}`)
+	if err != nil {
+		return nil, err
+	}
 
-	if result == "" {
+	if result.String() == "" {
 		return nil, errors.ErrNoFeeds
 	}
 
 	var feeds []Feed
-	if err := json.Unmarshal([]byte(result), &feeds); err != nil {
+	if err := json.Unmarshal([]byte(result.String()), &feeds); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal feeds: %w", err)
 	}
 
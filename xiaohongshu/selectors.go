@@ -0,0 +1,238 @@
+package xiaohongshu
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// 内置的默认选择器
+//
+//go:embed selectors.json
+var defaultSelectorsData []byte
+
+// Selectors 页面元素的 CSS 选择器。
+// 默认值来自内置的 selectors.json，启动时可通过配置文件或环境变量覆盖，页面改版时无需重新发布即可修正。
+// 带 %s、%d 的选择器是格式模板，覆盖时需保留相同的占位符。
+type Selectors struct {
+	Login struct {
+		UserChannel string `json:"user_channel"` // 已登录时侧边栏的“我”
+		QRCode      string `json:"qrcode"`
+	} `json:"login"`
+
+	Explore struct {
+		App            string `json:"app"`
+		SidebarProfile string `json:"sidebar_profile"`
+	} `json:"explore"`
+
+	Captcha struct {
+		Widget string `json:"widget"`
+	} `json:"captcha"`
+
+	FeedDetail struct {
+		Inaccessible      string `json:"inaccessible"` // 笔记不可访问时的提示容器
+		LikeButton        string `json:"like_button"`
+		CollectButton     string `json:"collect_button"`
+		CommentsContainer string `json:"comments_container"`
+		TotalComments     string `json:"total_comments"`
+		ParentComment     string `json:"parent_comment"`
+		ShowMore          string `json:"show_more"`
+		NoComments        string `json:"no_comments"`
+		End               string `json:"end"`
+		Scroller          string `json:"scroller"` // 触发滚轮事件的容器，逗号分隔的多个选择器按顺序尝试
+	} `json:"feed_detail"`
+
+	Comment struct {
+		InputBox    string `json:"input_box"`
+		Input       string `json:"input"`
+		Submit      string `json:"submit"`
+		Item        string `json:"item"`
+		ByID        string `json:"by_id"` // 格式模板，参数为评论ID
+		ReplyButton string `json:"reply_button"`
+	} `json:"comment"`
+
+	UserProfile struct {
+		FollowButton    string `json:"follow_button"`
+		UnfollowConfirm string `json:"unfollow_confirm"` // 取消关注二次确认按钮，按文字匹配
+		Tab             string `json:"tab"`
+	} `json:"user_profile"`
+
+	Search struct {
+		FilterButton string `json:"filter_button"`
+		FilterPanel  string `json:"filter_panel"`
+		FilterOption string `json:"filter_option"` // 格式模板，参数为筛选分组和选项的序号
+	} `json:"search"`
+
+	Publish struct {
+		UploadContent  string `json:"upload_content"`
+		Tab            string `json:"tab"`
+		Popover        string `json:"popover"`
+		UploadInput    string `json:"upload_input"`
+		FileInput      string `json:"file_input"` // 视频上传时 upload_input 找不到的兜底
+		UploadedImage  string `json:"uploaded_image"`
+		TitleInput     string `json:"title_input"`
+		TitleTooLong   string `json:"title_too_long"`
+		ContentEditor  string `json:"content_editor"`
+		ContentHint    string `json:"content_hint"` // content_editor 找不到时，按占位文字查找正文输入框
+		ContentTooLong string `json:"content_too_long"`
+		TopicContainer string `json:"topic_container"`
+		TopicItem      string `json:"topic_item"`
+		Submit         string `json:"submit"`
+		VideoSubmit    string `json:"video_submit"`
+	} `json:"publish"`
+
+	Draft struct {
+		SaveButton string `json:"save_button"`
+		BoxEntry   string `json:"box_entry"`   // 草稿箱入口，按文字匹配
+		TypeTab    string `json:"type_tab"`    // 图文/视频草稿标签，按文字匹配
+		EditButton string `json:"edit_button"` // 草稿的编辑按钮，按文字匹配
+		Item       string `json:"item"`
+		Title      string `json:"title"`
+		Time       string `json:"time"`
+	} `json:"draft"`
+}
+
+var (
+	defaultSelectors Selectors
+	currentSelectors atomic.Pointer[Selectors]
+)
+
+func init() {
+	if err := decodeSelectors(defaultSelectorsData, &defaultSelectors); err != nil {
+		panic(fmt.Sprintf("内置选择器配置无效: %v", err))
+	}
+	s := defaultSelectors
+	currentSelectors.Store(&s)
+}
+
+// sel 返回当前生效的选择器
+func sel() *Selectors {
+	return currentSelectors.Load()
+}
+
+// CurrentSelectors 返回当前生效的选择器
+func CurrentSelectors() Selectors {
+	return *sel()
+}
+
+// ParseSelectors 在内置默认值上应用覆盖配置，返回合并后的选择器。
+// 每个 data 为 JSON 或 YAML，只需写出要覆盖的选择器，后面的覆盖前面的；未知的名称视为错误。
+func ParseSelectors(overrides ...[]byte) (Selectors, error) {
+	s := defaultSelectors
+	for _, data := range overrides {
+		if len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+		if err := decodeSelectors(data, &s); err != nil {
+			return Selectors{}, err
+		}
+	}
+	if err := validateSelectors(reflect.ValueOf(s), reflect.ValueOf(defaultSelectors), ""); err != nil {
+		return Selectors{}, err
+	}
+	return s, nil
+}
+
+// LoadSelectors 读取覆盖配置文件和环境变量中的选择器并生效，配置文件不存在时忽略。
+// 环境变量中的配置优先于文件。
+func LoadSelectors(path, env string) error {
+	var overrides [][]byte
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to read selectors config")
+	}
+	if err == nil {
+		logrus.Infof("加载选择器配置: %s", path)
+		overrides = append(overrides, data)
+	}
+	if env != "" {
+		logrus.Info("加载环境变量 SELECTORS_OVERRIDE 中的选择器配置")
+		overrides = append(overrides, []byte(env))
+	}
+
+	s, err := ParseSelectors(overrides...)
+	if err != nil {
+		return err
+	}
+	currentSelectors.Store(&s)
+	return nil
+}
+
+// GetSelectorsPath 获取选择器覆盖配置文件路径，可通过环境变量 SELECTORS_PATH 指定
+func GetSelectorsPath() string {
+	path := os.Getenv("SELECTORS_PATH")
+	if path == "" {
+		path = "selectors.yaml"
+	}
+	return path
+}
+
+// decodeSelectors 将 JSON 或 YAML 覆盖到 s 上。JSON 是 YAML 的子集，统一按 YAML 解析后转换为 JSON 解码。
+func decodeSelectors(data []byte, s *Selectors) error {
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return errors.Wrap(err, "failed to parse selectors config")
+	}
+
+	jsonData, err := json.Marshal(raw)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse selectors config")
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(jsonData))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(s); err != nil {
+		return errors.Wrap(err, "invalid selectors config")
+	}
+	return nil
+}
+
+// validateSelectors 检查选择器不为空，且格式模板的占位符与默认值一致
+func validateSelectors(v, def reflect.Value, prefix string) error {
+	for i := 0; i < v.NumField(); i++ {
+		name := strings.Split(v.Type().Field(i).Tag.Get("json"), ",")[0]
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		field, defField := v.Field(i), def.Field(i)
+		if field.Kind() == reflect.Struct {
+			if err := validateSelectors(field, defField, name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		value := strings.TrimSpace(field.String())
+		if value == "" {
+			return errors.Errorf("选择器 %s 不能为空", name)
+		}
+		if placeholders(value) != placeholders(defField.String()) {
+			return errors.Errorf("选择器 %s 的占位符与默认值 %q 不一致", name, defField.String())
+		}
+	}
+	return nil
+}
+
+// placeholders 返回格式模板中的占位符，如 "%d%d"
+func placeholders(format string) string {
+	var b strings.Builder
+	for i := 0; i < len(format)-1; i++ {
+		if format[i] != '%' {
+			continue
+		}
+		b.WriteString(format[i : i+2])
+		i++
+	}
+	return b.String()
+}
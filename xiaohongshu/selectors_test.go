@@ -0,0 +1,52 @@
+package xiaohongshu
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSelectors(t *testing.T) {
+	s, err := ParseSelectors()
+	require.NoError(t, err)
+	require.Equal(t, "div.d-input input", s.Publish.TitleInput)
+	require.Equal(t, "#comment-%s", s.Comment.ByID)
+
+	s, err = ParseSelectors(
+		[]byte("publish:\n  title_input: \"input.title\"\ncomment:\n  by_id: \"#c-%s\"\n"),
+		[]byte(`{"publish": {"title_input": "input.new-title"}}`),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "input.new-title", s.Publish.TitleInput, "后面的覆盖前面的")
+	require.Equal(t, "#c-%s", s.Comment.ByID)
+	require.Equal(t, "div.ql-editor", s.Publish.ContentEditor, "未覆盖的沿用默认值")
+
+	_, err = ParseSelectors([]byte(`{"publish": {"unknown": "x"}}`))
+	require.Error(t, err)
+
+	_, err = ParseSelectors([]byte(`{"comment": {"by_id": "#comment"}}`))
+	require.ErrorContains(t, err, "comment.by_id")
+
+	_, err = ParseSelectors([]byte(`{"search": {"filter_button": " "}}`))
+	require.ErrorContains(t, err, "search.filter_button")
+}
+
+func TestLoadSelectors(t *testing.T) {
+	t.Cleanup(func() {
+		s := defaultSelectors
+		currentSelectors.Store(&s)
+	})
+
+	path := filepath.Join(t.TempDir(), "selectors.yaml")
+	require.NoError(t, LoadSelectors(path, ""), "配置文件不存在时使用默认值")
+	require.Equal(t, defaultSelectors, CurrentSelectors())
+
+	require.NoError(t, os.WriteFile(path, []byte("captcha:\n  widget: .captcha-a\n"), 0600))
+	require.NoError(t, LoadSelectors(path, `{"captcha": {"widget": ".captcha-b"}}`))
+	require.Equal(t, ".captcha-b", CurrentSelectors().Captcha.Widget)
+
+	require.Error(t, LoadSelectors(path, "captcha: ["))
+	require.Equal(t, ".captcha-b", CurrentSelectors().Captcha.Widget, "出错时保留原配置")
+}
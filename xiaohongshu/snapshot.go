@@ -0,0 +1,45 @@
+package xiaohongshu
+
+import (
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/sirupsen/logrus"
+)
+
+// PageSnapshot 页面快照，用于排查操作失败的原因
+type PageSnapshot struct {
+	URL        string
+	Title      string
+	HTML       string
+	Screenshot []byte // 整页 PNG 截图
+}
+
+// CaptureSnapshot 保存页面当前的地址、HTML 和整页截图。
+// 尽力而为，某一项获取失败时对应字段为空。
+func CaptureSnapshot(page *rod.Page) *PageSnapshot {
+	snapshot := &PageSnapshot{}
+
+	if info, err := page.Info(); err == nil {
+		snapshot.URL = info.URL
+		snapshot.Title = info.Title
+	} else {
+		logrus.Warnf("快照读取页面信息失败: %v", err)
+	}
+
+	if html, err := page.HTML(); err == nil {
+		snapshot.HTML = html
+	} else {
+		logrus.Warnf("快照读取页面 HTML 失败: %v", err)
+	}
+
+	screenshot, err := page.Screenshot(true, &proto.PageCaptureScreenshot{
+		Format: proto.PageCaptureScreenshotFormatPng,
+	})
+	if err == nil {
+		snapshot.Screenshot = screenshot
+	} else {
+		logrus.Warnf("快照截图失败: %v", err)
+	}
+
+	return snapshot
+}
@@ -0,0 +1,94 @@
+package xiaohongshu
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
+	"github.com/ysmood/gson"
+)
+
+// StepError 浏览器操作中某一步失败，记录步骤、选择器和失败时的页面地址，便于排查页面改版等问题
+type StepError struct {
+	Step     string // 失败的步骤，如 "打开笔记详情页"
+	Selector string // 相关的选择器，可为空
+	URL      string // 失败时的页面地址，可为空
+	Err      error
+}
+
+func (e *StepError) Error() string {
+	var details []string
+	if e.Selector != "" {
+		details = append(details, "selector: "+e.Selector)
+	}
+	if e.URL != "" {
+		details = append(details, "url: "+e.URL)
+	}
+
+	msg := e.Step + "失败"
+	if len(details) > 0 {
+		msg += " (" + strings.Join(details, ", ") + ")"
+	}
+	if e.Err != nil {
+		msg = fmt.Sprintf("%s: %v", msg, e.Err)
+	}
+	return msg
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// stepError 记录当前页面地址，包装某一步的错误
+func stepError(page *rod.Page, step, selector string, err error) error {
+	return &StepError{Step: step, Selector: selector, URL: pageURL(page), Err: err}
+}
+
+// pageURL 返回页面当前地址，读取失败时返回空字符串
+func pageURL(page *rod.Page) string {
+	info, err := page.Info()
+	if err != nil {
+		return ""
+	}
+	return info.URL
+}
+
+// navigate 打开页面并等待 DOM 稳定
+func navigate(page *rod.Page, step, url string) error {
+	if err := page.Navigate(url); err != nil {
+		return &StepError{Step: step, URL: url, Err: err}
+	}
+	if err := page.WaitDOMStable(time.Second, 0); err != nil {
+		return &StepError{Step: step, URL: url, Err: fmt.Errorf("等待页面加载: %w", err)}
+	}
+	return nil
+}
+
+// waitInitialState 等待页面注入 window.__INITIAL_STATE__
+func waitInitialState(page *rod.Page, step string) error {
+	if err := page.Wait(rod.Eval(`() => window.__INITIAL_STATE__ !== undefined`)); err != nil {
+		return stepError(page, step, "", err)
+	}
+	return nil
+}
+
+// findElement 查找元素，找不到时返回 SELECTOR_NOT_FOUND 错误
+func findElement(page *rod.Page, what, selector string) (*rod.Element, error) {
+	el, err := page.Element(selector)
+	if err != nil {
+		return nil, stepError(page, "查找"+what, selector,
+			myerrors.Wrap(myerrors.CodeSelectorNotFound, err, "页面元素未找到"))
+	}
+	return el, nil
+}
+
+// evalJS 在页面中执行脚本并返回结果
+func evalJS(page *rod.Page, step, js string, params ...any) (gson.JSON, error) {
+	res, err := page.Eval(js, params...)
+	if err != nil {
+		return gson.JSON{}, stepError(page, step, "", err)
+	}
+	return res.Value, nil
+}
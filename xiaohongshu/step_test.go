@@ -0,0 +1,28 @@
+package xiaohongshu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	myerrors "github.com/xpzouying/xiaohongshu-mcp/errors"
+)
+
+func TestStepError(t *testing.T) {
+	err := &StepError{
+		Step:     "查找发布按钮",
+		Selector: "div.submit div.d-button-content",
+		URL:      "https://creator.xiaohongshu.com/publish/publish",
+		Err:      myerrors.Wrap(myerrors.CodeSelectorNotFound, context.DeadlineExceeded, "页面元素未找到"),
+	}
+
+	require.Equal(t, "查找发布按钮失败 (selector: div.submit div.d-button-content, url: https://creator.xiaohongshu.com/publish/publish): 页面元素未找到: context deadline exceeded", err.Error())
+
+	wrapped := fmt.Errorf("小红书发布失败: %w", err)
+	require.Equal(t, myerrors.CodeSelectorNotFound, myerrors.CodeOf(wrapped))
+	require.True(t, errors.Is(wrapped, context.DeadlineExceeded))
+
+	require.Equal(t, "打开发现页失败: boom", (&StepError{Step: "打开发现页", Err: errors.New("boom")}).Error())
+}
@@ -7,14 +7,57 @@ import (
 	"time"
 
 	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
 )
 
+// ProfileTab 用户主页的笔记标签页
+type ProfileTab string
+
+const (
+	ProfileTabNotes     ProfileTab = "notes"     // 笔记
+	ProfileTabCollected ProfileTab = "collected" // 收藏
+	ProfileTabLiked     ProfileTab = "liked"     // 点赞
+)
+
+// ErrProfileTabHidden 用户没有公开收藏或点赞
+var ErrProfileTabHidden = errors.New("该用户未公开此标签页")
+
+// profileTabs 标签页名称及其在 user.notes 中的下标
+var profileTabs = map[ProfileTab]struct {
+	label string
+	index int
+}{
+	ProfileTabNotes:     {label: "笔记", index: 0},
+	ProfileTabCollected: {label: "收藏", index: 1},
+	ProfileTabLiked:     {label: "点赞", index: 2},
+}
+
+// ParseProfileTab 解析标签页，空字符串默认为笔记
+func ParseProfileTab(s string) (ProfileTab, error) {
+	if s == "" {
+		return ProfileTabNotes, nil
+	}
+	if _, ok := profileTabs[ProfileTab(s)]; !ok {
+		return "", errors.Errorf("未知的标签页: %s，可选 notes|collected|liked", s)
+	}
+	return ProfileTab(s), nil
+}
+
+// UserProfilePagedResponse 分页获取的用户主页
+type UserProfilePagedResponse struct {
+	UserProfileResponse
+	Tab     ProfileTab `json:"tab"`
+	HasMore bool       `json:"has_more"`
+}
+
 type UserProfileAction struct {
 	page *rod.Page
 }
 
 func NewUserProfileAction(page *rod.Page) *UserProfileAction {
-	pp := page.Timeout(60 * time.Second)
+	pp := page.Timeout(configs.Get().Timeouts.PageAction.Duration)
 	return &UserProfileAction{page: pp}
 }
 
@@ -22,18 +65,134 @@ func NewUserProfileAction(page *rod.Page) *UserProfileAction {
 func (u *UserProfileAction) UserProfile(ctx context.Context, userID, xsecToken string) (*UserProfileResponse, error) {
 	page := u.page.Context(ctx)
 
-	searchURL := makeUserProfileURL(userID, xsecToken)
-	page.MustNavigate(searchURL)
-	page.MustWaitStable()
+	if err := openUserProfilePage(page, makeUserProfileURL(userID, xsecToken)); err != nil {
+		return nil, err
+	}
+
+	if err := checkCaptcha(page); err != nil {
+		return nil, err
+	}
 
 	return u.extractUserProfileData(page)
 }
 
+// UserProfilePaged 获取用户基本信息，并从指定标签页滚动加载笔记。
+// opts.Limit 为 0 时只返回标签页首屏内容。收藏、点赞未公开时返回 ErrProfileTabHidden。
+func (u *UserProfileAction) UserProfilePaged(ctx context.Context, userID, xsecToken string, tab ProfileTab, opts PageOptions) (*UserProfilePagedResponse, error) {
+	page := u.page.Context(ctx)
+
+	if err := openUserProfilePage(page, makeUserProfileURL(userID, xsecToken)); err != nil {
+		return nil, err
+	}
+
+	if err := checkCaptcha(page); err != nil {
+		return nil, err
+	}
+
+	profile, err := u.extractUserProfileData(page)
+	if err != nil {
+		return nil, err
+	}
+
+	if tab != ProfileTabNotes {
+		if err := openProfileTab(page, tab); err != nil {
+			return nil, err
+		}
+	}
+
+	read := func() ([]Feed, error) {
+		return readProfileTabFeeds(page, tab)
+	}
+
+	response := &UserProfilePagedResponse{
+		UserProfileResponse: UserProfileResponse{
+			UserBasicInfo: profile.UserBasicInfo,
+			Interactions:  profile.Interactions,
+		},
+		Tab: tab,
+	}
+
+	var result *PagedFeeds
+	if opts.Paged() {
+		result, err = scrollCollectFeeds(page, opts, read)
+	} else {
+		result, err = firstScreenFeeds(page, read)
+	}
+	if err != nil {
+		return nil, err
+	}
+	response.Feeds = result.Feeds
+	response.HasMore = result.HasMore
+
+	return response, nil
+}
+
+// openProfileTab 切换到收藏或点赞标签页，标签不存在说明用户未公开
+func openProfileTab(page *rod.Page, tab ProfileTab) error {
+	label := profileTabs[tab].label
+
+	elem, err := page.Timeout(configs.Get().Timeouts.ElementProbe.Duration).ElementR(sel().UserProfile.Tab, "^"+label)
+	if err != nil {
+		return errors.Wrapf(ErrProfileTabHidden, "没有找到%s标签", label)
+	}
+	if err := elem.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return errors.Wrapf(err, "切换到%s标签失败", label)
+	}
+
+	// 等待标签页内容加载
+	sleepRange(delays().ActionSettle)
+	return nil
+}
+
+// readProfileTabFeeds 读取标签页当前已加载的笔记
+func readProfileTabFeeds(page *rod.Page, tab ProfileTab) ([]Feed, error) {
+	result, err := page.Eval(`(index) => {
+		if (window.__INITIAL_STATE__ &&
+		    window.__INITIAL_STATE__.user &&
+		    window.__INITIAL_STATE__.user.notes) {
+			const notes = window.__INITIAL_STATE__.user.notes;
+			const data = notes.value !== undefined ? notes.value : notes._value;
+			if (data && data[index]) {
+				return JSON.stringify(data[index]);
+			}
+		}
+		return "";
+	}`, profileTabs[tab].index)
+	if err != nil {
+		return nil, errors.Wrap(err, "读取主页笔记失败")
+	}
+
+	raw := result.Value.String()
+	if raw == "" {
+		return []Feed{}, nil
+	}
+
+	var feeds []Feed
+	if err := json.Unmarshal([]byte(raw), &feeds); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal notes: %w", err)
+	}
+
+	return feeds, nil
+}
+
+// openUserProfilePage 打开用户主页并等待页面稳定
+func openUserProfilePage(page *rod.Page, url string) error {
+	if err := page.Navigate(url); err != nil {
+		return &StepError{Step: "打开用户主页", URL: url, Err: err}
+	}
+	if err := page.WaitStable(time.Second); err != nil {
+		return stepError(page, "等待用户主页加载", "", err)
+	}
+	return nil
+}
+
 // extractUserProfileData 从页面中提取用户资料数据的通用方法
 func (u *UserProfileAction) extractUserProfileData(page *rod.Page) (*UserProfileResponse, error) {
-	page.MustWait(`() => window.__INITIAL_STATE__ !== undefined`)
+	if err := waitInitialState(page, "等待用户主页数据"); err != nil {
+		return nil, err
+	}
 
-	userDataResult := page.MustEval(`() => {
+	userData, err := evalJS(page, "读取用户资料", `() => {
 		if (window.__INITIAL_STATE__ &&
 		    window.__INITIAL_STATE__.user &&
 		    window.__INITIAL_STATE__.user.userPageData) {
@@ -44,14 +203,18 @@ func (u *UserProfileAction) extractUserProfileData(page *rod.Page) (*UserProfile
 			}
 		}
 		return "";
-	}`).String()
+	}`)
+	if err != nil {
+		return nil, err
+	}
+	userDataResult := userData.String()
 
 	if userDataResult == "" {
 		return nil, fmt.Errorf("user.userPageData.value not found in __INITIAL_STATE__")
 	}
 
 	// 2. 获取用户帖子：window.__INITIAL_STATE__.user.notes.value
-	notesResult := page.MustEval(`() => {
+	notes, err := evalJS(page, "读取用户笔记", `() => {
 		if (window.__INITIAL_STATE__ &&
 		    window.__INITIAL_STATE__.user &&
 		    window.__INITIAL_STATE__.user.notes) {
@@ -63,7 +226,11 @@ func (u *UserProfileAction) extractUserProfileData(page *rod.Page) (*UserProfile
 			}
 		}
 		return "";
-	}`).String()
+	}`)
+	if err != nil {
+		return nil, err
+	}
+	notesResult := notes.String()
 
 	if notesResult == "" {
 		return nil, fmt.Errorf("user.notes.value not found in __INITIAL_STATE__")
@@ -116,7 +283,13 @@ func (u *UserProfileAction) GetMyProfileViaSidebar(ctx context.Context) (*UserPr
 	}
 
 	// 等待页面加载完成并获取 __INITIAL_STATE__
-	page.MustWaitStable()
+	if err := page.WaitStable(time.Second); err != nil {
+		return nil, stepError(page, "等待个人主页加载", "", err)
+	}
+
+	if err := checkCaptcha(page); err != nil {
+		return nil, err
+	}
 
 	return u.extractUserProfileData(page)
 }